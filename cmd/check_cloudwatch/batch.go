@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/container"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type batchCheck struct {
+	Host        string                     `json:"host"`
+	Service     string                     `json:"service"`
+	Queries     []awstypes.MetricDataQuery `json:"queries"`
+	Warning     string                     `json:"warning"`
+	Critical    string                     `json:"critical"`
+	Datapoints  string                     `json:"datapoints"`
+	MissingData string                     `json:"missingData"`
+}
+
+func runBatch() alert.ReturnCode {
+	flags, err := parseBatchFlags()
+
+	log.SetVerbosity(*flags.verbosity)
+
+	summary := newSummary(flags.summaryFormat(), *flags.verbosity)
+
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	summary.serviceName = *flags.serviceName
+
+	passive := newSummary(formatClassic, 0)
+
+	passive.serviceName = *flags.serviceName
+	passive.longOutput = *flags.longOutput
+
+	if *flags.outputTemplate != "" {
+		passive.template, err = parseOutputTemplate(*flags.outputTemplate)
+
+		if err != nil {
+			return summary.print(alert.Unknown, err.Error())
+		}
+	}
+
+	checks, err := readBatchChecks(*flags.batchFile)
+
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	queries := make([][]awstypes.MetricDataQuery, 0, len(checks))
+
+	for _, c := range checks {
+		queries = append(queries, c.Queries)
+	}
+
+	batch := cloudwatch.NewBatch(*flags.duration, queries, newOptions(flags), *flags.timeout)
+
+	now := time.Now()
+
+	results := batch.GetMetricSeries(now)
+
+	duration := time.Duration(*flags.duration) * time.Minute
+
+	commands := make([]string, 0, len(checks))
+	counts := map[alert.ReturnCode]int{}
+
+	for i, c := range checks {
+		returnCode, output := evaluateBatchCheck(passive, c, results[i], now, duration)
+
+		log.V(2).Debug().
+			Str("host", c.Host).
+			Str("service", c.Service).
+			Str("status", returnCode.String()).
+			Msg(output.String())
+
+		counts[returnCode]++
+
+		commands = append(commands, formatCommand(now, c.Host, c.Service, returnCode, passive.line(returnCode, output)))
+	}
+
+	if err := writeCommands(*flags.commandFile, commands); err != nil {
+		return summary.print(alert.Unknown, fmt.Sprintf("unable to write check results: %s", err))
+	}
+
+	return summary.print(alert.OK, fmt.Sprintf(
+		"%d checks submitted: %d OK, %d WARNING, %d CRITICAL, %d UNKNOWN",
+		len(checks), counts[alert.OK], counts[alert.Warning], counts[alert.Critical], counts[alert.Unknown],
+	))
+}
+
+func readBatchChecks(path string) ([]batchCheck, error) {
+	var b []byte
+	var err error
+
+	if path == "-" {
+		b, err = io.ReadAll(container.GetInputIO())
+	} else {
+		b, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return nil, errors.NewArgumentErrorWithError(err, "file", path)
+	}
+
+	var checks []batchCheck
+
+	if err := json.Unmarshal(b, &checks); err != nil {
+		return nil, errors.NewArgumentErrorWithError(err, "file", path)
+	}
+
+	if len(checks) == 0 {
+		return nil, errors.NewArgumentErrorWithMessage("file must contain an array of checks", "file", path)
+	}
+
+	for _, c := range checks {
+		if c.Host == "" || c.Service == "" {
+			return nil, errors.NewArgumentErrorWithMessage("every check must have host and service", "file", path)
+		}
+
+		if strings.ContainsAny(c.Host+c.Service, commandSeparators) {
+			return nil, errors.NewArgumentErrorWithMessage("host and service must not contain ';' or line breaks", "file", path)
+		}
+	}
+
+	return checks, nil
+}
+
+func evaluateBatchCheck(
+	summary summary, c batchCheck, result cloudwatch.BatchResult,
+	now time.Time, duration time.Duration,
+) (alert.ReturnCode, pluginOutput) {
+	datapoints := c.Datapoints
+
+	if datapoints == "" {
+		datapoints = "1/1"
+	}
+
+	missingData := c.MissingData
+
+	if missingData == "" {
+		missingData = "ignore"
+	}
+
+	checker, err := alert.NewChecker(c.Warning, c.Critical, datapoints, missingData)
+
+	if err != nil {
+		return alert.Unknown, pluginOutput{text: err.Error()}
+	}
+
+	if result.Err != nil {
+		return alert.Unknown, pluginOutput{text: result.Err.Error()}
+	}
+
+	s := result.Series[0]
+
+	returnCode, err := checker.CheckTimeSeries(s.Timestamps, s.Values, now, time.Duration(s.Period)*time.Second, duration)
+
+	if err != nil {
+		return returnCode, pluginOutput{text: err.Error()}
+	}
+
+	value, timestamp := s.Latest()
+	b1, b2, b3, b4 := checker.Result()
+
+	return returnCode, summary.appendDatapoints(
+		summary.build(c.Warning, c.Critical, datapoints, s.Name(), s.Unit, value, timestamp, b1, b2, b3, b4),
+		checker.Datapoints(),
+	)
+}
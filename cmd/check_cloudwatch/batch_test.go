@@ -0,0 +1,268 @@
+package main
+
+import (
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func Test_runBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		checks                  string
+		createCommandFile       bool
+		cloudwatchClientFactory func(types.Options) (types.Client, error)
+	}
+
+	type expected struct {
+		returnCode alert.ReturnCode
+		commands   []string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	checks := `[
+		{"host":"web01","service":"CPU","queries":[{"Id":"m1","Expression":"TIME_SERIES(1)"}],"warning":"0:1.5","critical":"0:2.5"},
+		{"host":"web02","service":"CPU","queries":[{"Id":"m1","Expression":"TIME_SERIES(3)"}],"warning":"0:1.5","critical":"0:2.5"},
+		{"host":"web03","service":"CPU","queries":[{"Id":"m1","Expression":"TIME_SERIES(3)"}],"warning":"0:1.5","critical":"INVALID"}
+	]`
+
+	timestamp := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "submitted",
+			args: args{
+				checks:            checks,
+				createCommandFile: true,
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id:         aws.String("c0_m1"),
+								Label:      aws.String("c0_m1"),
+								Timestamps: []time.Time{timestamp},
+								Values:     []float64{1.0},
+							},
+							{
+								Id:         aws.String("c1_m1"),
+								Label:      aws.String("c1_m1"),
+								Timestamps: []time.Time{timestamp},
+								Values:     []float64{3.0},
+							},
+							{
+								Id:         aws.String("c2_m1"),
+								Label:      aws.String("c2_m1"),
+								Timestamps: []time.Time{timestamp},
+								Values:     []float64{3.0},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil).Once()
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				returnCode: alert.OK,
+				commands: []string{
+					"PROCESS_SERVICE_CHECK_RESULT;web01;CPU;0;CLOUDWATCH OK: m1 = 1 | m1=1;0:1.5;0:2.5;;",
+					"PROCESS_SERVICE_CHECK_RESULT;web02;CPU;2;CLOUDWATCH CRITICAL: m1 = 3; above thresholds = 1 | m1=3;0:1.5;0:2.5;; datapoints_crit=1;;1/1;;",
+					"PROCESS_SERVICE_CHECK_RESULT;web03;CPU;3;CLOUDWATCH UNKNOWN: ",
+				},
+			},
+		},
+		{
+			name: "API error",
+			args: args{
+				checks:            checks,
+				createCommandFile: true,
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.GetMetricDataOutput{}, goerrors.New("")).Once()
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				returnCode: alert.OK,
+				commands: []string{
+					"PROCESS_SERVICE_CHECK_RESULT;web01;CPU;3;CLOUDWATCH UNKNOWN: ",
+					"PROCESS_SERVICE_CHECK_RESULT;web02;CPU;3;CLOUDWATCH UNKNOWN: ",
+					"PROCESS_SERVICE_CHECK_RESULT;web03;CPU;3;CLOUDWATCH UNKNOWN: ",
+				},
+			},
+		},
+		{
+			name: "missing command file",
+			args: args{
+				checks:            checks,
+				createCommandFile: false,
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.GetMetricDataOutput{}, goerrors.New("")).Once()
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				returnCode: alert.Unknown,
+			},
+		},
+		{
+			name: "invalid file",
+			args: args{
+				checks:            `{}`,
+				createCommandFile: true,
+			},
+			expected: expected{
+				returnCode: alert.Unknown,
+				commands:   []string{},
+			},
+		},
+		{
+			name: "client error",
+			args: args{
+				checks:            checks,
+				createCommandFile: true,
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					return nil, goerrors.New("")
+				},
+			},
+			expected: expected{
+				returnCode: alert.OK,
+				commands: []string{
+					"PROCESS_SERVICE_CHECK_RESULT;web01;CPU;3;CLOUDWATCH UNKNOWN: ",
+					"PROCESS_SERVICE_CHECK_RESULT;web02;CPU;3;CLOUDWATCH UNKNOWN: ",
+					"PROCESS_SERVICE_CHECK_RESULT;web03;CPU;3;CLOUDWATCH UNKNOWN: ",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			checksFile := filepath.Join(dir, "checks.json")
+			commandFile := filepath.Join(dir, "nagios.cmd")
+
+			if err := os.WriteFile(checksFile, []byte(tc.args.checks), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.args.createCommandFile {
+				if err := os.WriteFile(commandFile, []byte{}, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			helper.SetLogOutputDiscard(t)
+			helper.SetCommandArgs(t, []string{"batch", "-f", checksFile, "--command-file", commandFile})
+			helper.SetCloudWatchClientFactory(t, tc.args.cloudwatchClientFactory)
+
+			assert.Equal(tc.expected.returnCode, runBatch(), "alert.ReturnCode")
+
+			if tc.expected.commands == nil {
+				return
+			}
+
+			b, _ := os.ReadFile(commandFile)
+
+			lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+
+			if len(b) == 0 {
+				lines = []string{}
+			}
+
+			assert.Len(lines, len(tc.expected.commands), "commands")
+
+			for i, line := range lines {
+				assert.Regexp(regexp.MustCompile(`^\[\d+\] `), line, "timestamp")
+				assert.True(strings.HasPrefix(line[strings.Index(line, "] ")+2:], tc.expected.commands[i]), line)
+			}
+		})
+	}
+}
+
+func Test_readBatchChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     string
+		expected error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "valid",
+			args:     `[{"host":"web01","service":"CPU","queries":[{"Id":"m1","Expression":"TIME_SERIES(1)"}],"warning":"80","critical":"90"}]`,
+			expected: nil,
+		},
+		{
+			name:     "empty",
+			args:     `[]`,
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name:     "invalid JSON",
+			args:     `[`,
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name:     "missing service",
+			args:     `[{"host":"web01","queries":[{"Id":"m1","Expression":"TIME_SERIES(1)"}]}]`,
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name:     "semicolon in host",
+			args:     `[{"host":"web01;web02","service":"CPU","queries":[{"Id":"m1","Expression":"TIME_SERIES(1)"}]}]`,
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name:     "line break in service",
+			args:     `[{"host":"web01","service":"CPU\n[0] SHUTDOWN_PROGRAM","queries":[{"Id":"m1","Expression":"TIME_SERIES(1)"}]}]`,
+			expected: &errors.ArgumentError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetInputIO(t, strings.NewReader(tc.args))
+
+			_, err := readBatchChecks("-")
+
+			if tc.expected != nil {
+				assert.ErrorAs(err, tc.expected, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+			}
+		})
+	}
+}
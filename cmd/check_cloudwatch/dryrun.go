@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+)
+
+type dryRunThresholds struct {
+	Warning     string `json:"warning"`
+	Critical    string `json:"critical"`
+	Datapoints  string `json:"datapoints"`
+	MissingData string `json:"missingData"`
+}
+
+type dryRunOutput struct {
+	Input           *cloudwatch.GetMetricDataInput `json:"input"`
+	Thresholds      dryRunThresholds               `json:"thresholds"`
+	QueryThresholds map[string]dryRunThresholds    `json:"queryThresholds,omitempty"`
+}
+
+func formatDryRun(
+	input *cloudwatch.GetMetricDataInput, checker alert.Checker, checkers map[string]alert.Checker,
+	missingData string,
+) (string, error) {
+	newThresholds := func(c alert.Checker) dryRunThresholds {
+		warnRange, criticalRange, datapointsThreshold := c.Thresholds()
+
+		return dryRunThresholds{
+			Warning:     warnRange,
+			Critical:    criticalRange,
+			Datapoints:  datapointsThreshold,
+			MissingData: missingData,
+		}
+	}
+
+	output := dryRunOutput{
+		Input:      input,
+		Thresholds: newThresholds(checker),
+	}
+
+	if len(checkers) != 0 {
+		output.QueryThresholds = map[string]dryRunThresholds{}
+
+		for id, c := range checkers {
+			output.QueryThresholds[id] = newThresholds(c)
+		}
+	}
+
+	b, err := json.MarshalIndent(output, "", "  ")
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func printDryRun(
+	input *cloudwatch.GetMetricDataInput, checker alert.Checker, checkers map[string]alert.Checker,
+	missingData string,
+) error {
+	s, err := formatDryRun(input, checker, checkers, missingData)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(s)
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"testing"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_run_dryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		returnCode      alert.ReturnCode
+		queries         int
+		window          float64
+		thresholds      dryRunThresholds
+		queryThresholds map[string]dryRunThresholds
+	}
+
+	type testCase struct {
+		name     string
+		args     []string
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "queries",
+			args: []string{
+				"--warning",
+				"0.0:1.5",
+				"--critical",
+				"0.0:2.5",
+				"--datapoints",
+				"3/5",
+				"--missing-data",
+				"breaching",
+				"--queries",
+				`[{"Id":"m1","MetricStat":{"Metric":{"Namespace":"AWS/EC2","MetricName":"CPUUtilization"},"Period":60,"Stat":"Average"},"ReturnData":false},{"Id":"e1","Expression":"m1*2"}]`,
+				"--threshold",
+				"e1=10,20",
+				"--duration",
+				"15",
+				"--dry-run",
+			},
+			expected: expected{
+				returnCode: alert.OK,
+				queries:    2,
+				window:     15 * 60,
+				thresholds: dryRunThresholds{
+					Warning:     "0.0:1.5",
+					Critical:    "0.0:2.5",
+					Datapoints:  "3/5",
+					MissingData: "breaching",
+				},
+				queryThresholds: map[string]dryRunThresholds{
+					"e1": {
+						Warning:     "10",
+						Critical:    "20",
+						Datapoints:  "3/5",
+						MissingData: "breaching",
+					},
+				},
+			},
+		},
+		{
+			name: "metric",
+			args: []string{
+				"--namespace",
+				"AWS/EC2",
+				"--metric",
+				"CPUUtilization",
+				"--warning",
+				"80",
+				"--dry-run",
+			},
+			expected: expected{
+				returnCode: alert.OK,
+				queries:    1,
+				window:     60 * 60,
+				thresholds: dryRunThresholds{
+					Warning:     "80",
+					Critical:    "",
+					Datapoints:  "1/1",
+					MissingData: "ignore",
+				},
+			},
+		},
+		{
+			name: "invalid queries",
+			args: []string{
+				"--queries",
+				`{`,
+				"--dry-run",
+			},
+			expected: expected{
+				returnCode: alert.Unknown,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+			helper.SetCommandArgs(t, tc.args)
+			helper.SetCloudWatchClientFactory(t, func(types.Options) (types.Client, error) {
+				t.Error("API client must not be created")
+
+				return nil, goerrors.New("")
+			})
+
+			ci := make(chan bool)
+
+			co := captureStdout(t, ci)
+
+			returnCode := run()
+
+			ci <- true
+
+			output := <-co
+
+			assert.Equal(tc.expected.returnCode, returnCode, "alert.ReturnCode")
+
+			if tc.expected.returnCode != alert.OK {
+				return
+			}
+
+			var o dryRunOutput
+
+			if err := json.Unmarshal([]byte(output), &o); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Len(o.Input.MetricDataQueries, tc.expected.queries, "queries")
+			assert.Equal(tc.expected.window, o.Input.EndTime.Sub(*o.Input.StartTime).Seconds(), "time window")
+			assert.Equal(tc.expected.thresholds, o.Thresholds, "thresholds")
+			assert.Equal(tc.expected.queryThresholds, o.QueryThresholds, "query thresholds")
+		})
+	}
+}
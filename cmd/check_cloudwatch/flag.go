@@ -14,13 +14,57 @@ type flags struct {
 	warnRange           *string
 	criticalRange       *string
 	datapointsThreshold *string
+	missingData         *string
+	thresholds          *[]string
 	queries             *string
+	vars                *[]string
+	namespace           *string
+	metricName          *string
+	dimensions          *[]string
+	stat                *string
+	period              *int
+	sql                 *string
+	alarmNames          *[]string
+	alarmPrefix         *string
+	logGroups           *[]string
+	logsQuery           *string
+	field               *string
+	emptyValue          *string
 	duration            *int
 	timeout             *int
+	retries             *int
+	region              *string
+	profile             *string
+	endpointURL         *string
+	roleARN             *string
+	externalID          *string
+	roleSessionName     *string
+	cacheTTL            *int
+	cacheDir            *string
+	record              *string
+	replay              *string
+	dryRun              *bool
+	allSeries           *bool
+	top                 *int
 	classicOutput       *bool
+	outputFormat        *string
+	longOutput          *bool
+	outputTemplate      *string
+	serviceName         *string
 	verbosity           *int
 	showVersion         *bool
 	showHelp            *bool
+	batchFile           *string
+	commandFile         *string
+	spoolDir            *string
+	passiveHost         *string
+	passiveService      *string
+	icingaURL           *string
+	icingaUser          *string
+	icingaPasswordFile  *string
+	icingaCert          *string
+	icingaKey           *string
+	icingaCA            *string
 }
 
 func newFlags() flags {
@@ -50,19 +94,193 @@ func parseFlags() (flags, error) {
 		os.Exit(0)
 	}
 
-	if *f.queries == "" {
+	if *f.queries != "" && *f.metricName != "" {
+		return f, errors.NewArgumentErrorWithMessage("queries and metric are mutually exclusive", "metric", *f.metricName)
+	}
+
+	if *f.sql != "" && (*f.queries != "" || *f.metricName != "") {
+		return f, errors.NewArgumentErrorWithMessage("sql cannot be combined with queries or metric", "sql", *f.sql)
+	}
+
+	if len(*f.alarmNames) != 0 && *f.alarmPrefix != "" {
+		return f, errors.NewArgumentErrorWithMessage("alarm name and alarm prefix are mutually exclusive", "alarm-prefix", *f.alarmPrefix)
+	}
+
+	if f.isAlarmMode() && (*f.queries != "" || *f.metricName != "" || *f.sql != "") {
+		return f, errors.NewArgumentErrorWithMessage("alarms cannot be combined with queries, metric or sql", "alarm-name", strings.Join(*f.alarmNames, ","))
+	}
+
+	if f.isLogsMode() && (*f.queries != "" || *f.metricName != "" || *f.sql != "" || f.isAlarmMode()) {
+		return f, errors.NewArgumentErrorWithMessage("logs query cannot be combined with queries, metric, sql or alarms", "logs-query", *f.logsQuery)
+	}
+
+	if f.isLogsMode() && len(*f.logGroups) == 0 {
+		return f, errors.NewArgumentErrorWithMessage("log group must be specified with logs query", "log-group", "")
+	}
+
+	if *f.emptyValue != "" && (!f.isLogsMode() || *f.field == "") {
+		return f, errors.NewArgumentErrorWithMessage("empty value must be specified with logs query and field", "empty-value", *f.emptyValue)
+	}
+
+	if *f.queries == "" && *f.metricName == "" && *f.sql == "" && !f.isAlarmMode() && !f.isLogsMode() {
 		return f, errors.NewArgumentErrorWithMessage("queries must be an array of MetricDataQuery objects", "queries", "")
 	}
 
+	if *f.metricName == "" && (*f.namespace != "" || len(*f.dimensions) != 0 || pflag.CommandLine.Changed("stat")) {
+		return f, errors.NewArgumentErrorWithMessage("namespace, dimension and stat require metric", "metric", "")
+	}
+
+	if *f.metricName != "" && *f.namespace == "" {
+		return f, errors.NewArgumentErrorWithMessage("namespace must be specified with metric", "namespace", "")
+	}
+
+	if *f.period <= 0 {
+		return f, errors.NewArgumentErrorWithMessage("period must be a positive number", "period", strconv.Itoa(*f.period))
+	}
+
+	if *f.top < 0 {
+		return f, errors.NewArgumentErrorWithMessage("top must not be a negative number", "top", strconv.Itoa(*f.top))
+	}
+
+	if *f.dryRun && (f.isAlarmMode() || f.isLogsMode()) {
+		return f, errors.NewArgumentErrorWithMessage("dry run is only supported for metric queries", "dry-run", "true")
+	}
+
+	if *f.record != "" && *f.replay != "" {
+		return f, errors.NewArgumentErrorWithMessage("record and replay are mutually exclusive", "replay", *f.replay)
+	}
+
+	if (*f.record != "" || *f.replay != "") && *f.cacheTTL != 0 {
+		return f, errors.NewArgumentErrorWithMessage("record and replay cannot be used with cache", "cache-ttl", strconv.Itoa(*f.cacheTTL))
+	}
+
+	if f.isPassiveMode() {
+		if *f.passiveHost == "" || *f.passiveService == "" {
+			return f, errors.NewArgumentErrorWithMessage("passive host and passive service must be specified for passive results", "passive-host", *f.passiveHost)
+		}
+
+		if strings.ContainsAny(*f.passiveHost, commandSeparators) {
+			return f, errors.NewArgumentErrorWithMessage("passive host must not contain ';' or line breaks", "passive-host", *f.passiveHost)
+		}
+
+		if strings.ContainsAny(*f.passiveService, commandSeparators) {
+			return f, errors.NewArgumentErrorWithMessage("passive service must not contain ';' or line breaks", "passive-service", *f.passiveService)
+		}
+
+		targets := 0
+
+		for _, t := range []string{*f.commandFile, *f.spoolDir, *f.icingaURL} {
+			if t != "" {
+				targets++
+			}
+		}
+
+		if targets != 1 {
+			return f, errors.NewArgumentErrorWithMessage("one of command file, spool directory or Icinga URL must be specified for passive results", "command-file", *f.commandFile)
+		}
+
+		if *f.icingaPasswordFile != "" && *f.icingaUser == "" {
+			return f, errors.NewArgumentErrorWithMessage("Icinga password file requires Icinga user", "icinga-password-file", *f.icingaPasswordFile)
+		}
+
+		if (*f.icingaCert == "") != (*f.icingaKey == "") {
+			return f, errors.NewArgumentErrorWithMessage("Icinga certificate and key must be specified together", "icinga-cert", *f.icingaCert)
+		}
+	}
+
+	return f, f.validateRequestFlags()
+}
+
+func parseBatchFlags() (flags, error) {
+	setupBatchParser()
+
+	f := newFlags()
+
+	f.defineBatchFlags()
+
+	if err := pflag.CommandLine.Parse(os.Args[2:]); err != nil {
+		return f, errors.NewArgumentErrorWithError(err, "arguments", strings.Join(os.Args[2:], " "))
+	}
+
+	if *f.showHelp {
+		pflag.Usage()
+
+		os.Exit(0)
+	}
+
+	if *f.batchFile == "" {
+		return f, errors.NewArgumentErrorWithMessage("file of checks must be specified", "file", "")
+	}
+
+	if *f.commandFile == "" {
+		return f, errors.NewArgumentErrorWithMessage("command file must be specified", "command-file", "")
+	}
+
+	return f, f.validateRequestFlags()
+}
+
+func (f flags) validateRequestFlags() error {
 	if *f.duration <= 0 {
-		return f, errors.NewArgumentErrorWithMessage("time duration must be a positive number", "duration", strconv.Itoa(*f.duration))
+		return errors.NewArgumentErrorWithMessage("time duration must be a positive number", "duration", strconv.Itoa(*f.duration))
 	}
 
 	if *f.timeout <= 0 {
-		return f, errors.NewArgumentErrorWithMessage("timeout must be a positive number", "timeout", strconv.Itoa(*f.timeout))
+		return errors.NewArgumentErrorWithMessage("timeout must be a positive number", "timeout", strconv.Itoa(*f.timeout))
+	}
+
+	if *f.retries < 0 {
+		return errors.NewArgumentErrorWithMessage("retries must not be a negative number", "retries", strconv.Itoa(*f.retries))
+	}
+
+	if *f.cacheTTL < 0 {
+		return errors.NewArgumentErrorWithMessage("cache TTL must not be a negative number", "cache-ttl", strconv.Itoa(*f.cacheTTL))
+	}
+
+	format, err := parseOutputFormat(*f.outputFormat)
+
+	if err != nil {
+		return err
+	}
+
+	if *f.classicOutput && format != formatJSON && format != formatClassic {
+		return errors.NewArgumentErrorWithMessage("classic output cannot be combined with another output format", "output-format", *f.outputFormat)
+	}
+
+	if *f.serviceName == "" || strings.ContainsAny(*f.serviceName, "|\n") {
+		return errors.NewArgumentErrorWithMessage("service name must not be empty or contain '|' or line breaks", "service-name", *f.serviceName)
+	}
+
+	if *f.roleARN == "" && *f.externalID != "" {
+		return errors.NewArgumentErrorWithMessage("external ID requires role ARN", "external-id", *f.externalID)
 	}
 
-	return f, nil
+	return nil
+}
+
+func (f flags) summaryFormat() outputFormat {
+	if *f.classicOutput {
+		return formatClassic
+	}
+
+	format, err := parseOutputFormat(*f.outputFormat)
+
+	if err != nil {
+		return formatJSON
+	}
+
+	return format
+}
+
+func (f flags) isAlarmMode() bool {
+	return len(*f.alarmNames) != 0 || *f.alarmPrefix != ""
+}
+
+func (f flags) isLogsMode() bool {
+	return *f.logsQuery != ""
+}
+
+func (f flags) isPassiveMode() bool {
+	return *f.passiveHost != "" || *f.passiveService != "" || *f.commandFile != "" || *f.spoolDir != "" || *f.icingaURL != ""
 }
 
 func setupParser() {
@@ -79,8 +297,60 @@ func setupParser() {
 This plugin checks AWS CloudWatch metrics using GetMetricData API.
 
 Usage:
-  check_cloudwatch -q <queries> -w <range> -c <range> -p <datapoints>
-                   [-d <duration>] [-t <timeout>] [-C] [-v]
+  check_cloudwatch -q <queries> [-D <KEY=VALUE>...]
+                   -w <range> -c <range> -p <datapoints>
+                   [-T <Id=warn,crit[,n/m]>...] [-m <treatment>]
+                   [-d <duration>] [-t <timeout>] [--retries <count>]
+                   [--region <region>] [--profile <profile>] [--endpoint-url <URL>]
+                   [--role-arn <ARN> [--external-id <ID>] [--role-session-name <name>]]
+                   [--cache-ttl <seconds> [--cache-dir <path>]]
+                   [--dry-run] [--record <file> | --replay <file>]
+                   [--passive-host <host> --passive-service <service>
+                    (--command-file <path> | --spool-dir <path> | --icinga-url <URL>)]
+                   [--output-template <template>] [--service-name <name>]
+                   [-A] [-C | --output-format <format>] [-L] [-v]
+  check_cloudwatch --namespace <namespace> --metric <name>
+                   [--dimension <NAME=VALUE>...] [--stat <stat>] [--period <period>]
+                   -w <range> -c <range> -p <datapoints> ...
+  check_cloudwatch --sql <query> [--period <period>] [--top <N>]
+                   -w <range> -c <range> -p <datapoints> ...
+  check_cloudwatch (--alarm-name <name>... | --alarm-prefix <prefix>) ...
+  check_cloudwatch --log-group <name>... --logs-query <query>
+                   [--field <name> [--empty-value <value>]]
+                   -w <range> -c <range> -p <datapoints> ...
+  check_cloudwatch batch -f <file> --command-file <path> ...
+
+Options:
+`
+
+		fmt.Print(header + usage)
+
+		pflag.PrintDefaults()
+	}
+}
+
+func setupBatchParser() {
+	pflag.CommandLine.Init(os.Args[0]+" batch", pflag.ContinueOnError)
+
+	pflag.CommandLine.SetOutput(os.Stdout)
+
+	pflag.CommandLine.SortFlags = false
+
+	pflag.Usage = func() {
+		header := fmt.Sprintf("check_cloudwatch (v%s)\n", version)
+
+		usage := `
+This subcommand evaluates many checks with as few GetMetricData API calls as possible,
+and submits the results to Nagios as passive check results.
+
+Usage:
+  check_cloudwatch batch -f <file> --command-file <path>
+                   [-d <duration>] [-t <timeout>] [--retries <count>]
+                   [--region <region>] [--profile <profile>] [--endpoint-url <URL>]
+                   [--role-arn <ARN> [--external-id <ID>] [--role-session-name <name>]]
+                   [--cache-ttl <seconds> [--cache-dir <path>]]
+                   [--output-template <template>] [--service-name <name>]
+                   [-C | --output-format <format>] [-L] [-v]
 
 Options:
 `
@@ -97,7 +367,99 @@ func (f *flags) defineFlags() {
 		"",
 		""+
 			"An array of MetricDataQuery objects in `JSON` format.\n"+
-			"See the AWS GetMetricData API reference for details.",
+			"See the AWS GetMetricData API reference for details.\n"+
+			"Use '@path' to read them from a file, or '-' to read them from stdin.",
+	)
+
+	f.vars = pflag.StringArrayP(
+		"var", "D",
+		[]string{},
+		""+
+			"Set a variable for placeholders like '{{.KEY}}' in the queries, in the format\n"+
+			"'`KEY=VALUE`'. Can be repeated.",
+	)
+
+	f.namespace = pflag.String(
+		"namespace",
+		"",
+		"Set the `namespace` of the metric, instead of specifying queries.",
+	)
+
+	f.metricName = pflag.String(
+		"metric",
+		"",
+		"Set the `name` of the metric, instead of specifying queries.",
+	)
+
+	f.dimensions = pflag.StringArray(
+		"dimension",
+		[]string{},
+		"Set a dimension of the metric in the format '`NAME=VALUE`'. Can be repeated.",
+	)
+
+	f.stat = pflag.String(
+		"stat",
+		"Average",
+		"Set the `statistic` of the metric, such as 'Average', 'Sum' or 'p99'.\n",
+	)
+
+	f.period = pflag.Int(
+		"period",
+		300,
+		"Set the period in seconds of the metric.\n",
+	)
+
+	f.sql = pflag.String(
+		"sql",
+		"",
+		""+
+			"A Metrics Insights `query`, such as 'SELECT ... GROUP BY ...', instead of specifying\n"+
+			"queries. Every returned group is evaluated, using the period set by --period.\n"+
+			"Use '@path' to read it from a file, or '-' to read it from stdin.",
+	)
+
+	f.alarmNames = pflag.StringArray(
+		"alarm-name",
+		[]string{},
+		""+
+			"Check the state of the CloudWatch alarm with the given `name`, instead of specifying\n"+
+			"queries. Composite alarms are included. Can be repeated.",
+	)
+
+	f.alarmPrefix = pflag.String(
+		"alarm-prefix",
+		"",
+		"Check the state of every CloudWatch alarm whose name starts with the given `prefix`.",
+	)
+
+	f.logGroups = pflag.StringArray(
+		"log-group",
+		[]string{},
+		"Set the `name` of a log group to run the Logs Insights query against. Can be repeated.",
+	)
+
+	f.logsQuery = pflag.String(
+		"logs-query",
+		"",
+		""+
+			"A CloudWatch Logs Insights `query` to evaluate, instead of specifying metric queries.\n"+
+			"Use '@path' to read it from a file, or '-' to read it from stdin.",
+	)
+
+	f.field = pflag.String(
+		"field",
+		"",
+		""+
+			"Set the `name` of the numeric field to evaluate in the Logs Insights query results.\n"+
+			"Defaults to the first field not starting with '@'.",
+	)
+
+	f.emptyValue = pflag.String(
+		"empty-value",
+		"",
+		""+
+			"Evaluate the Logs Insights query as a single `value` when it returns no result rows,\n"+
+			"instead of reporting UNKNOWN. Requires --field.",
 	)
 
 	f.warnRange = pflag.StringP(
@@ -122,16 +484,126 @@ func (f *flags) defineFlags() {
 			"specified in the format '`n/m`'.\n",
 	)
 
-	f.duration = pflag.IntP(
-		"duration", "d",
-		60,
-		"Set the duration in minutes for which to retrieve metrics.\n",
+	f.thresholds = pflag.StringArrayP(
+		"threshold", "T",
+		[]string{},
+		""+
+			"Set the warning/critical ranges and optionally the data points for the\n"+
+			"query with the given Id. The `spec` should be in the format\n"+
+			"'Id=warn,crit[,n/m]'. Can be repeated. Every returned metric series is\n"+
+			"evaluated, and series of other queries use the -w, -c and -p options.",
 	)
 
-	f.timeout = pflag.IntP(
-		"timeout", "t",
-		10,
-		"Set the time in seconds before the plugin times out.\n",
+	f.missingData = pflag.StringP(
+		"missing-data", "m",
+		"ignore",
+		""+
+			"Set the `treatment` of missing data points, like TreatMissingData of\n"+
+			"CloudWatch alarms. One of 'ignore', 'missing', 'breaching' or 'notBreaching'.\n",
+	)
+
+	f.defineRequestFlags()
+
+	f.allSeries = pflag.BoolP(
+		"all-series", "A",
+		false,
+		""+
+			"Evaluate every returned metric series instead of only the first one.\n"+
+			"The worst status among the series is reported.",
+	)
+
+	f.top = pflag.Int(
+		"top",
+		0,
+		""+
+			"List only the `N` worst series above thresholds when several series are evaluated.\n"+
+			"Set to 0 to list all of them.",
+	)
+
+	f.dryRun = pflag.Bool(
+		"dry-run",
+		false,
+		""+
+			"Print the GetMetricData API request and the thresholds in JSON format, and exit\n"+
+			"without calling the API.",
+	)
+
+	f.record = pflag.String(
+		"record",
+		"",
+		"Record the CloudWatch API requests and responses to the JSON `file`.",
+	)
+
+	f.replay = pflag.String(
+		"replay",
+		"",
+		""+
+			"Replay the CloudWatch API responses recorded in the JSON `file` with --record,\n"+
+			"instead of calling the API. The check is evaluated at the time of the recording.",
+	)
+
+	f.passiveHost = pflag.String(
+		"passive-host",
+		"",
+		""+
+			"Submit the result as a passive check result for the given `host`, instead of\n"+
+			"printing it. Requires --passive-service and --command-file, --spool-dir or --icinga-url.",
+	)
+
+	f.passiveService = pflag.String(
+		"passive-service",
+		"",
+		"Set the `service` description to submit the passive check result for.",
+	)
+
+	f.commandFile = pflag.String(
+		"command-file",
+		"",
+		"Write the passive check result to the Nagios external command file at `path`.",
+	)
+
+	f.spoolDir = pflag.String(
+		"spool-dir",
+		"",
+		"Write the passive check result as a file into the Nagios check result directory at `path`.",
+	)
+
+	f.icingaURL = pflag.String(
+		"icinga-url",
+		"",
+		"Post the passive check result to the Icinga 2 API at the base `URL`, such as 'https://icinga:5665'.",
+	)
+
+	f.icingaUser = pflag.String(
+		"icinga-user",
+		"",
+		"Set the API `user` for basic authentication to the Icinga 2 API.",
+	)
+
+	f.icingaPasswordFile = pflag.String(
+		"icinga-password-file",
+		"",
+		""+
+			"Read the password of the API user from the file at `path`.\n"+
+			"Defaults to the ICINGA_PASSWORD environment variable.",
+	)
+
+	f.icingaCert = pflag.String(
+		"icinga-cert",
+		"",
+		"Set the `path` to the client certificate for authentication to the Icinga 2 API.",
+	)
+
+	f.icingaKey = pflag.String(
+		"icinga-key",
+		"",
+		"Set the `path` to the private key of the client certificate.",
+	)
+
+	f.icingaCA = pflag.String(
+		"icinga-ca",
+		"",
+		"Set the `path` to the CA certificate to verify the Icinga 2 API server with.",
 	)
 
 	f.classicOutput = pflag.BoolP(
@@ -140,6 +612,36 @@ func (f *flags) defineFlags() {
 		"Print status message in classic format.",
 	)
 
+	f.outputFormat = pflag.String(
+		"output-format",
+		formatJSON.String(),
+		""+
+			"Set the `format` of the status message: 'json', 'classic', 'checkmk' for Checkmk\n"+
+			"local checks, or 'sensu' for Sensu Go check results in JSON format.\n",
+	)
+
+	f.longOutput = pflag.BoolP(
+		"long-output", "L",
+		false,
+		""+
+			"Append a table of the evaluated data points with their timestamps, values and\n"+
+			"statuses to the status message, as long output of the plugin.",
+	)
+
+	f.outputTemplate = pflag.String(
+		"output-template",
+		"",
+		""+
+			"Render the status message from the `template` in Go text/template syntax instead\n"+
+			"of the built-in format. Prefix '@' to read it from a file.",
+	)
+
+	f.serviceName = pflag.String(
+		"service-name",
+		defaultServiceName,
+		"Set the `name` printed at the beginning of the status line.\n",
+	)
+
 	f.verbosity = pflag.CountP(
 		"verbose", "v",
 		"Enable extra information, with up to 3 verbosity levels.",
@@ -157,3 +659,141 @@ func (f *flags) defineFlags() {
 		"Print detailed help information.",
 	)
 }
+
+func (f *flags) defineBatchFlags() {
+	f.batchFile = pflag.StringP(
+		"file", "f",
+		"",
+		""+
+			"A `path` to the JSON file of checks to evaluate. Each check has 'host', 'service',\n"+
+			"'queries', 'warning', 'critical' and optionally 'datapoints' and 'missingData'.\n"+
+			"Use '-' to read it from stdin.",
+	)
+
+	f.commandFile = pflag.String(
+		"command-file",
+		"/usr/local/nagios/var/rw/nagios.cmd",
+		"Set the `path` to the Nagios external command file to write the check results to.\n",
+	)
+
+	f.defineRequestFlags()
+
+	f.classicOutput = pflag.BoolP(
+		"classic-output", "C",
+		false,
+		"Print status message in classic format.",
+	)
+
+	f.outputFormat = pflag.String(
+		"output-format",
+		formatJSON.String(),
+		""+
+			"Set the `format` of the status message: 'json', 'classic', 'checkmk' for Checkmk\n"+
+			"local checks, or 'sensu' for Sensu Go check results in JSON format.\n",
+	)
+
+	f.longOutput = pflag.BoolP(
+		"long-output", "L",
+		false,
+		""+
+			"Append a table of the evaluated data points with their timestamps, values and\n"+
+			"statuses to the status message, as long output of the plugin.",
+	)
+
+	f.outputTemplate = pflag.String(
+		"output-template",
+		"",
+		""+
+			"Render the status message from the `template` in Go text/template syntax instead\n"+
+			"of the built-in format. Prefix '@' to read it from a file.",
+	)
+
+	f.serviceName = pflag.String(
+		"service-name",
+		defaultServiceName,
+		"Set the `name` printed at the beginning of the status line.\n",
+	)
+
+	f.verbosity = pflag.CountP(
+		"verbose", "v",
+		"Enable extra information, with up to 3 verbosity levels.",
+	)
+
+	f.showHelp = pflag.BoolP(
+		"help", "h",
+		false,
+		"Print detailed help information.",
+	)
+}
+
+func (f *flags) defineRequestFlags() {
+	f.duration = pflag.IntP(
+		"duration", "d",
+		60,
+		"Set the duration in minutes for which to retrieve metrics.\n",
+	)
+
+	f.timeout = pflag.IntP(
+		"timeout", "t",
+		10,
+		"Set the time in seconds before the plugin times out.\n",
+	)
+
+	f.retries = pflag.Int(
+		"retries",
+		0,
+		""+
+			"Retry API requests failing with throttling or server errors up to `count` times.\n"+
+			"Retries back off exponentially and never exceed the timeout.",
+	)
+
+	f.region = pflag.String(
+		"region",
+		"",
+		"Set the AWS `region` to use. Defaults to the SDK configuration.",
+	)
+
+	f.profile = pflag.String(
+		"profile",
+		"",
+		"Set the `profile` in the shared AWS configuration files to use.",
+	)
+
+	f.endpointURL = pflag.String(
+		"endpoint-url",
+		"",
+		"Override the CloudWatch API endpoint with the given `URL`.",
+	)
+
+	f.roleARN = pflag.String(
+		"role-arn",
+		"",
+		"Assume the IAM role with the given `ARN` before calling CloudWatch.",
+	)
+
+	f.externalID = pflag.String(
+		"external-id",
+		"",
+		"Pass the external `ID` when assuming the role.",
+	)
+
+	f.roleSessionName = pflag.String(
+		"role-session-name",
+		"check_cloudwatch",
+		"Set the session `name` used when assuming the role.\n",
+	)
+
+	f.cacheTTL = pflag.Int(
+		"cache-ttl",
+		0,
+		""+
+			"Serve identical GetMetricData requests from an on-disk cache for `seconds`.\n"+
+			"Set to 0 to disable the cache.",
+	)
+
+	f.cacheDir = pflag.String(
+		"cache-dir",
+		"",
+		"Set the `path` to the cache directory. Defaults to 'check_cloudwatch' in the user cache directory.",
+	)
+}
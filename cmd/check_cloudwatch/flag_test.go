@@ -33,11 +33,469 @@ func Test_parseFlags(t *testing.T) {
 				"30",
 				"--timeout",
 				"5",
+				"--retries",
+				"3",
 				"--classic-output",
 				"-vvv",
 			},
 			expected: nil,
 		},
+		{
+			name: "queries from stdin",
+			args: []string{
+				"--warning",
+				"0.0:1.0",
+				"--queries",
+				"-",
+			},
+			expected: nil,
+		},
+		{
+			name: "metric",
+			args: []string{
+				"--namespace",
+				"AWS/EC2",
+				"--metric",
+				"CPUUtilization",
+				"--dimension",
+				"InstanceId=i-123",
+				"--stat",
+				"Maximum",
+				"--period",
+				"60",
+				"--warning",
+				"80",
+			},
+			expected: nil,
+		},
+		{
+			name: "queries and metric",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--namespace",
+				"AWS/EC2",
+				"--metric",
+				"CPUUtilization",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "namespace with queries",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--namespace",
+				"AWS/EC2",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "dimension with sql",
+			args: []string{
+				"--sql",
+				"SELECT AVG(CPUUtilization) FROM SCHEMA(\"AWS/EC2\", InstanceId)",
+				"--dimension",
+				"InstanceId=i-1",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "stat without metric",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--stat",
+				"Maximum",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "empty value without field",
+			args: []string{
+				"--log-group",
+				"/app/web",
+				"--logs-query",
+				"stats count(*) as errors",
+				"--empty-value",
+				"0",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "empty value without logs query",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--field",
+				"errors",
+				"--empty-value",
+				"0",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "metric without namespace",
+			args: []string{
+				"--metric",
+				"CPUUtilization",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "non-positive period",
+			args: []string{
+				"--namespace",
+				"AWS/EC2",
+				"--metric",
+				"CPUUtilization",
+				"--period",
+				"0",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "role",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--role-arn",
+				"arn:aws:iam::123456789012:role/monitoring",
+				"--external-id",
+				"EXTERNAL-ID",
+				"--role-session-name",
+				"nagios",
+			},
+			expected: nil,
+		},
+		{
+			name: "external ID without role",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--external-id",
+				"EXTERNAL-ID",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "alarm names",
+			args: []string{
+				"--alarm-name",
+				"web-cpu",
+				"--alarm-name",
+				"web-health",
+			},
+			expected: nil,
+		},
+		{
+			name: "alarm prefix",
+			args: []string{
+				"--alarm-prefix",
+				"web-",
+			},
+			expected: nil,
+		},
+		{
+			name: "alarm name and prefix",
+			args: []string{
+				"--alarm-name",
+				"web-cpu",
+				"--alarm-prefix",
+				"web-",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "alarm and queries",
+			args: []string{
+				"--alarm-name",
+				"web-cpu",
+				"--queries",
+				`{"a":true}`,
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "logs query",
+			args: []string{
+				"--log-group",
+				"/app/web",
+				"--logs-query",
+				"stats count(*) as errors",
+				"--field",
+				"errors",
+			},
+			expected: nil,
+		},
+		{
+			name: "logs query without log group",
+			args: []string{
+				"--logs-query",
+				"stats count(*) as errors",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "logs query and queries",
+			args: []string{
+				"--log-group",
+				"/app/web",
+				"--logs-query",
+				"stats count(*) as errors",
+				"--queries",
+				`{"a":true}`,
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "sql",
+			args: []string{
+				"--sql",
+				`SELECT AVG(Errors) FROM SCHEMA("AWS/Lambda", FunctionName) GROUP BY FunctionName`,
+				"--top",
+				"5",
+			},
+			expected: nil,
+		},
+		{
+			name: "sql and metric",
+			args: []string{
+				"--sql",
+				`SELECT AVG(Errors) FROM SCHEMA("AWS/Lambda", FunctionName) GROUP BY FunctionName`,
+				"--namespace",
+				"AWS/EC2",
+				"--metric",
+				"CPUUtilization",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "negative top",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--top",
+				"-1",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive command file",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU",
+				"--command-file",
+				"/usr/local/nagios/var/rw/nagios.cmd",
+			},
+			expected: nil,
+		},
+		{
+			name: "passive without service",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--spool-dir",
+				"/usr/local/nagios/var/spool/checkresults",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive host with semicolon",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01;web02",
+				"--passive-service",
+				"CPU",
+				"--command-file",
+				"/usr/local/nagios/var/rw/nagios.cmd",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive service with line break",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU\r\n[0] SHUTDOWN_PROGRAM",
+				"--spool-dir",
+				"/usr/local/nagios/var/spool/checkresults",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive without target",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive with both targets",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU",
+				"--command-file",
+				"/usr/local/nagios/var/rw/nagios.cmd",
+				"--spool-dir",
+				"/usr/local/nagios/var/spool/checkresults",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive Icinga",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU",
+				"--icinga-url",
+				"https://icinga:5665",
+				"--icinga-cert",
+				"client.crt",
+				"--icinga-key",
+				"client.key",
+			},
+			expected: nil,
+		},
+		{
+			name: "passive Icinga without key",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU",
+				"--icinga-url",
+				"https://icinga:5665",
+				"--icinga-cert",
+				"client.crt",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "passive Icinga password file without user",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--passive-host",
+				"web01",
+				"--passive-service",
+				"CPU",
+				"--icinga-url",
+				"https://icinga:5665",
+				"--icinga-password-file",
+				"/etc/icinga-password",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "negative retries",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--retries",
+				"-1",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "negative cache TTL",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--cache-ttl",
+				"-1",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "empty service name",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--service-name",
+				"",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "unknown output format",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--output-format",
+				"xml",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "classic output and output format",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"-C",
+				"--output-format",
+				"sensu",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "record and replay",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--record",
+				"a.json",
+				"--replay",
+				"b.json",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "record with cache",
+			args: []string{
+				"--queries",
+				`{"a":true}`,
+				"--record",
+				"a.json",
+				"--cache-ttl",
+				"60",
+			},
+			expected: &errors.ArgumentError{},
+		},
+		{
+			name: "dry run with alarms",
+			args: []string{
+				"--alarm-name",
+				"web-cpu",
+				"--dry-run",
+			},
+			expected: &errors.ArgumentError{},
+		},
 		{
 			name: "unknown flag",
 			args: []string{
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type outputFormat int
+
+const (
+	formatJSON outputFormat = iota
+	formatClassic
+	formatCheckmk
+	formatSensu
+)
+
+type sensuCheckResult struct {
+	Check   sensuCheck    `json:"check"`
+	Metrics *sensuMetrics `json:"metrics,omitempty"`
+}
+
+type sensuCheck struct {
+	Metadata           sensuMetadata `json:"metadata"`
+	Status             int           `json:"status"`
+	Output             string        `json:"output"`
+	OutputMetricFormat string        `json:"output_metric_format"`
+	Executed           int64         `json:"executed"`
+}
+
+type sensuMetadata struct {
+	Name string `json:"name"`
+}
+
+type sensuMetrics struct {
+	Points []sensuMetricPoint `json:"points"`
+}
+
+type sensuMetricPoint struct {
+	Name      string  `json:"name"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+var sensuNamePattern = regexp.MustCompile(`[^A-Za-z0-9_.\-]`)
+
+func (f outputFormat) String() string {
+	switch f {
+	case formatJSON:
+		return "json"
+	case formatClassic:
+		return "classic"
+	case formatCheckmk:
+		return "checkmk"
+	case formatSensu:
+		return "sensu"
+	default:
+		return "-"
+	}
+}
+
+func parseOutputFormat(s string) (outputFormat, error) {
+	for _, f := range []outputFormat{formatJSON, formatClassic, formatCheckmk, formatSensu} {
+		if s == f.String() {
+			log.V(3).Trace().
+				Str("package", "main").
+				Str("output_format", f.String()).
+				Send()
+
+			return f, nil
+		}
+	}
+
+	return formatJSON, errors.NewArgumentErrorWithMessage("output format must be one of 'json', 'classic', 'checkmk' or 'sensu'", "output-format", s)
+}
+
+func formatLocalCheck(serviceName string, returnCode alert.ReturnCode, output pluginOutput) string {
+	metrics := []string{}
+
+	for _, e := range output.perfdata {
+		levels := make([]string, 4)
+
+		for i := range min(len(e.levels), len(levels)) {
+			if _, err := strconv.ParseFloat(e.levels[i], 64); err == nil {
+				levels[i] = e.levels[i]
+			}
+		}
+
+		metric := fmt.Sprintf("%s=%g", strings.ReplaceAll(sanitizeLabel(e.label), " ", "_"), e.value)
+
+		if l := strings.TrimRight(strings.Join(levels, ";"), ";"); l != "" {
+			metric += ";" + l
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	m := strings.Join(metrics, "|")
+
+	if m == "" {
+		m = "-"
+	}
+
+	name := serviceName
+
+	if strings.Contains(name, " ") {
+		name = `"` + name + `"`
+	}
+
+	text := output.text
+
+	if output.longOutput != "" {
+		text += "\n" + output.longOutput
+	}
+
+	return fmt.Sprintf("%d %s %s %s", int(returnCode), name, m, strings.ReplaceAll(text, "\n", `\n`))
+}
+
+func formatSensuCheckResult(now time.Time, serviceName string, returnCode alert.ReturnCode, output string, perfdata []perfdataEntry) (string, error) {
+	result := sensuCheckResult{
+		Check: sensuCheck{
+			Metadata: sensuMetadata{
+				Name: sensuNamePattern.ReplaceAllString(serviceName, "_"),
+			},
+			Status:             int(returnCode),
+			Output:             output,
+			OutputMetricFormat: "nagios_perfdata",
+			Executed:           now.Unix(),
+		},
+	}
+
+	points := []sensuMetricPoint{}
+
+	for _, e := range perfdata {
+		points = append(points, sensuMetricPoint{
+			Name:      sanitizeLabel(e.label),
+			Value:     e.value,
+			Timestamp: now.Unix(),
+		})
+	}
+
+	if len(points) != 0 {
+		result.Metrics = &sensuMetrics{
+			Points: points,
+		}
+	}
+
+	b, err := json.Marshal(result)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
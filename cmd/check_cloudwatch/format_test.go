@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseOutputFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		format outputFormat
+		err    error
+	}
+
+	type testCase struct {
+		name     string
+		args     string
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "json",
+			args: "json",
+			expected: expected{
+				format: formatJSON,
+				err:    nil,
+			},
+		},
+		{
+			name: "classic",
+			args: "classic",
+			expected: expected{
+				format: formatClassic,
+				err:    nil,
+			},
+		},
+		{
+			name: "checkmk",
+			args: "checkmk",
+			expected: expected{
+				format: formatCheckmk,
+				err:    nil,
+			},
+		},
+		{
+			name: "sensu",
+			args: "sensu",
+			expected: expected{
+				format: formatSensu,
+				err:    nil,
+			},
+		},
+		{
+			name: "unknown",
+			args: "xml",
+			expected: expected{
+				format: formatJSON,
+				err:    &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := parseOutputFormat(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+			}
+
+			assert.Equal(tc.expected.format, format, "format")
+		})
+	}
+}
+
+func Test_formatLocalCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		serviceName string
+		returnCode  alert.ReturnCode
+		output      pluginOutput
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "perfdata",
+			args: args{
+				serviceName: "CLOUDWATCH",
+				returnCode:  alert.Warning,
+				output: pluginOutput{
+					text: "CPUUtilization = 85.3; above thresholds = 1",
+					perfdata: []perfdataEntry{
+						newPerfdata("CPUUtilization", "Percent", 85.3, "80", "90"),
+						{label: "datapoints_warn", value: 1, levels: []string{"1/1", "", "", ""}},
+					},
+				},
+			},
+			expected: "1 CLOUDWATCH CPUUtilization=85.3;80;90;0;100|datapoints_warn=1 CPUUtilization = 85.3; above thresholds = 1",
+		},
+		{
+			name: "ranges",
+			args: args{
+				serviceName: "RDS PROD",
+				returnCode:  alert.OK,
+				output: pluginOutput{
+					text: "m1 = 0.5",
+					perfdata: []perfdataEntry{
+						newPerfdata("m1", "", 0.5, "0:1", "@2:3"),
+						{label: "free memory", value: 1024, uom: "B", levels: []string{"", "", "0", ""}},
+					},
+				},
+			},
+			expected: `0 "RDS PROD" m1=0.5|free_memory=1024;;;0 m1 = 0.5`,
+		},
+		{
+			name: "without perfdata",
+			args: args{
+				serviceName: "CLOUDWATCH",
+				returnCode:  alert.Unknown,
+				output:      pluginOutput{text: "no metric data results returned"},
+			},
+			expected: "3 CLOUDWATCH - no metric data results returned",
+		},
+		{
+			name: "long output",
+			args: args{
+				serviceName: "CLOUDWATCH",
+				returnCode:  alert.OK,
+				output: pluginOutput{
+					text:       "m1 = 0.5",
+					perfdata:   []perfdataEntry{newPerfdata("m1", "", 0.5, "", "")},
+					longOutput: "timestamp  value  status\n2022-09-19T10:20:00Z  0.5  OK",
+				},
+			},
+			expected: `0 CLOUDWATCH m1=0.5 m1 = 0.5\ntimestamp  value  status\n2022-09-19T10:20:00Z  0.5  OK`,
+		},
+		{
+			name: "pipe in text",
+			args: args{
+				serviceName: "CLOUDWATCH",
+				returnCode:  alert.Critical,
+				output: pluginOutput{
+					text:     "m1 = 3, see https://runbooks.example.com/?view=a|b",
+					perfdata: []perfdataEntry{newPerfdata("m1 a=b", "", 3, "1", "2")},
+				},
+			},
+			expected: "2 CLOUDWATCH m1_a_b=3;1;2 m1 = 3, see https://runbooks.example.com/?view=a|b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, formatLocalCheck(tc.args.serviceName, tc.args.returnCode, tc.args.output), "output")
+		})
+	}
+}
+
+func Test_formatSensuCheckResult(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	output, err := formatSensuCheckResult(
+		now, "RDS PROD", alert.Critical,
+		"RDS PROD CRITICAL: m1 = 3; above thresholds = 1 | m1=3;0:1;0:2;; datapoints_crit=1;;1/1;;",
+		[]perfdataEntry{
+			newPerfdata("m1", "", 3, "0:1", "0:2"),
+			{label: "datapoints_crit", value: 1, levels: []string{"", "1/1", "", ""}},
+		},
+	)
+
+	assert.Nil(err, "is not error")
+
+	var result sensuCheckResult
+
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal("RDS_PROD", result.Check.Metadata.Name, "name")
+	assert.Equal(2, result.Check.Status, "status")
+	assert.Equal("RDS PROD CRITICAL: m1 = 3; above thresholds = 1 | m1=3;0:1;0:2;; datapoints_crit=1;;1/1;;", result.Check.Output, "output")
+	assert.Equal("nagios_perfdata", result.Check.OutputMetricFormat, "output metric format")
+	assert.Equal(now.Unix(), result.Check.Executed, "executed")
+	assert.Equal(
+		&sensuMetrics{
+			Points: []sensuMetricPoint{
+				{Name: "m1", Value: 3, Timestamp: now.Unix()},
+				{Name: "datapoints_crit", Value: 1, Timestamp: now.Unix()},
+			},
+		},
+		result.Metrics,
+		"metrics",
+	)
+
+	output, err = formatSensuCheckResult(now, "CLOUDWATCH", alert.Unknown, "CLOUDWATCH UNKNOWN: no metric data results returned", []perfdataEntry{})
+
+	assert.Nil(err, "is not error")
+	assert.NotContains(output, `"metrics"`, "without metrics")
+}
+
+func Test_summary_print_format(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     outputFormat
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "checkmk",
+			args:     formatCheckmk,
+			expected: "2 CLOUDWATCH m1=3 m1 = 3",
+		},
+		{
+			name:     "sensu",
+			args:     formatSensu,
+			expected: `"output":"CLOUDWATCH CRITICAL: m1 = 3 | m1=3;0:1;0:2;;"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSummary(tc.args, 0)
+
+			ci := make(chan bool)
+
+			co := captureStdout(t, ci)
+
+			s.printOutput(alert.Critical, pluginOutput{
+				text:     "m1 = 3",
+				perfdata: []perfdataEntry{newPerfdata("m1", "", 3, "0:1", "0:2")},
+			})
+
+			ci <- true
+
+			assert.Contains(<-co, tc.expected, "output message")
+		})
+	}
+}
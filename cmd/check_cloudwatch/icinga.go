@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type icingaTarget struct {
+	url          string
+	user         string
+	passwordFile string
+	cert         string
+	key          string
+	ca           string
+	timeout      int
+}
+
+const icingaPasswordEnv string = "ICINGA_PASSWORD"
+
+type icingaCheckResult struct {
+	Type            string   `json:"type"`
+	Service         string   `json:"service"`
+	ExitStatus      int      `json:"exit_status"`
+	PluginOutput    string   `json:"plugin_output"`
+	PerformanceData []string `json:"performance_data"`
+	CheckSource     string   `json:"check_source,omitempty"`
+	ExecutionStart  int64    `json:"execution_start"`
+	ExecutionEnd    int64    `json:"execution_end"`
+}
+
+type icingaResponse struct {
+	Results []struct {
+		Code   float64 `json:"code"`
+		Status string  `json:"status"`
+	} `json:"results"`
+	Status string `json:"status"`
+}
+
+func (i icingaTarget) submit(now time.Time, host string, service string, returnCode alert.ReturnCode, output pluginOutput) error {
+	text := output.text
+
+	if output.longOutput != "" {
+		text += "\n" + output.longOutput
+	}
+
+	perfdata := make([]string, 0, len(output.perfdata))
+
+	for _, e := range output.perfdata {
+		perfdata = append(perfdata, e.String())
+	}
+
+	source, _ := os.Hostname()
+
+	body, err := json.Marshal(icingaCheckResult{
+		Type:            "Service",
+		Service:         host + "!" + service,
+		ExitStatus:      int(returnCode),
+		PluginOutput:    text,
+		PerformanceData: perfdata,
+		CheckSource:     source,
+		ExecutionStart:  now.Unix(),
+		ExecutionEnd:    now.Unix(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	client, err := i.client()
+
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimSuffix(i.url, "/") + "/v1/actions/process-check-result"
+
+	log.V(3).Trace().
+		Str("package", "main").
+		Str("url", endpoint).
+		RawJSON("body", body).
+		Msg("posting check result to Icinga 2 API")
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	if i.user != "" {
+		password, err := i.password()
+
+		if err != nil {
+			return err
+		}
+
+		req.SetBasicAuth(i.user, password)
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return err
+	}
+
+	log.V(3).Trace().
+		Str("package", "main").
+		Int("status_code", resp.StatusCode).
+		Bytes("body", b).
+		Msg("Icinga 2 API response")
+
+	r := icingaResponse{}
+
+	if err := json.Unmarshal(b, &r); err != nil && resp.StatusCode < 300 {
+		return fmt.Errorf("invalid Icinga 2 API response: %w", err)
+	}
+
+	if 300 <= resp.StatusCode {
+		if r.Status != "" {
+			return fmt.Errorf("unexpected response from Icinga 2 API: %s: %s", resp.Status, r.Status)
+		}
+
+		return fmt.Errorf("unexpected response from Icinga 2 API: %s", resp.Status)
+	}
+
+	for _, result := range r.Results {
+		if 300 <= result.Code {
+			return fmt.Errorf("unexpected response from Icinga 2 API: %g: %s", result.Code, result.Status)
+		}
+	}
+
+	return nil
+}
+
+func (i icingaTarget) password() (string, error) {
+	if i.passwordFile == "" {
+		return os.Getenv(icingaPasswordEnv), nil
+	}
+
+	log.V(3).Trace().
+		Str("package", "main").
+		Str("path", i.passwordFile).
+		Msg("reading Icinga 2 API password from file")
+
+	b, err := os.ReadFile(i.passwordFile)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func (i icingaTarget) client() (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+
+	if i.cert != "" {
+		cert, err := tls.LoadX509KeyPair(i.cert, i.key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if i.ca != "" {
+		b, err := os.ReadFile(i.ca)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in %s", i.ca)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Timeout: time.Duration(i.timeout) * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeServerCA(t *testing.T, server *httptest.Server, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "ca.crt")
+
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func writeClientCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "check_cloudwatch"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certPath, keyPath
+}
+
+func Test_icingaTarget_submit(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		clientCert   bool
+		user         string
+		password     string
+		passwordFile bool
+		statusCode   int
+		response     string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected bool
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "basic auth",
+			args: args{
+				user:         "root",
+				password:     "secret\n",
+				passwordFile: true,
+				statusCode:   http.StatusOK,
+				response:     `{"results":[{"code":200.0,"status":"Successfully processed check result for object 'web01!CPU'."}]}`,
+			},
+			expected: true,
+		},
+		{
+			name: "password from environment",
+			args: args{
+				user:       "root",
+				password:   "secret",
+				statusCode: http.StatusOK,
+				response:   `{"results":[{"code":200.0,"status":"Successfully processed check result for object 'web01!CPU'."}]}`,
+			},
+			expected: true,
+		},
+		{
+			name: "client certificate",
+			args: args{
+				clientCert: true,
+				statusCode: http.StatusOK,
+				response:   `{"results":[{"code":200.0,"status":"Successfully processed check result for object 'web01!CPU'."}]}`,
+			},
+			expected: true,
+		},
+		{
+			name: "wrong password",
+			args: args{
+				user:       "root",
+				password:   "wrong",
+				statusCode: http.StatusOK,
+				response:   `{"results":[]}`,
+			},
+			expected: false,
+		},
+		{
+			name: "wrong password file",
+			args: args{
+				user:         "root",
+				password:     "wrong",
+				passwordFile: true,
+				statusCode:   http.StatusOK,
+				response:     `{"results":[]}`,
+			},
+			expected: false,
+		},
+		{
+			name: "object not found",
+			args: args{
+				user:       "root",
+				password:   "secret",
+				statusCode: http.StatusNotFound,
+				response:   `{"error":404.0,"status":"No objects found."}`,
+			},
+			expected: false,
+		},
+		{
+			name: "result error",
+			args: args{
+				user:       "root",
+				password:   "secret",
+				statusCode: http.StatusOK,
+				response:   `{"results":[{"code":409.0,"status":"Service is not passive."}]}`,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			var received icingaCheckResult
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("/v1/actions/process-check-result", r.URL.Path, "path")
+				assert.Equal(http.MethodPost, r.Method, "method")
+
+				authenticated := len(r.TLS.PeerCertificates) != 0
+
+				if user, password, ok := r.BasicAuth(); ok {
+					authenticated = user == "root" && password == "secret"
+				}
+
+				if !authenticated {
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Error(err)
+				}
+
+				w.WriteHeader(tc.args.statusCode)
+
+				if _, err := w.Write([]byte(tc.args.response)); err != nil {
+					t.Error(err)
+				}
+			}))
+
+			server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+
+			server.StartTLS()
+
+			t.Cleanup(server.Close)
+
+			dir := t.TempDir()
+
+			target := icingaTarget{
+				url:     server.URL + "/",
+				user:    tc.args.user,
+				ca:      writeServerCA(t, server, dir),
+				timeout: 5,
+			}
+
+			t.Setenv(icingaPasswordEnv, "")
+
+			if tc.args.passwordFile {
+				target.passwordFile = filepath.Join(dir, "password")
+
+				if err := os.WriteFile(target.passwordFile, []byte(tc.args.password), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				t.Setenv(icingaPasswordEnv, tc.args.password)
+			}
+
+			if tc.args.clientCert {
+				target.cert, target.key = writeClientCert(t, dir)
+			}
+
+			output := newSummary(formatClassic, 0).withStatus(alert.Warning, pluginOutput{
+				text: "m1 = 1; above thresholds = 1, see https://runbooks.example.com/?view=a|b",
+				perfdata: []perfdataEntry{
+					newPerfdata("m1", "", 1, "0:0.5", "0:2.5"),
+					{label: "datapoints_warn", value: 1, levels: []string{"1/1", "", "", ""}},
+				},
+			})
+
+			err := target.submit(now, "web01", "CPU", alert.Warning, output)
+
+			if !tc.expected {
+				assert.NotNil(err, "is error")
+
+				return
+			}
+
+			assert.Nil(err, "is not error")
+
+			assert.Equal("Service", received.Type, "type")
+			assert.Equal("web01!CPU", received.Service, "service")
+			assert.Equal(1, received.ExitStatus, "exit status")
+			assert.Equal("CLOUDWATCH WARNING: m1 = 1; above thresholds = 1, see https://runbooks.example.com/?view=a/b", received.PluginOutput, "plugin output")
+			assert.Equal([]string{"m1=1;0:0.5;0:2.5;;", "datapoints_warn=1;1/1;;;"}, received.PerformanceData, "performance data")
+			assert.Equal(now.Unix(), received.ExecutionEnd, "execution end")
+		})
+	}
+}
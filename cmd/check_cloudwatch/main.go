@@ -10,6 +10,12 @@ import (
 var version = "0.0.0"
 
 func main() {
+	if 1 < len(os.Args) && os.Args[1] == "batch" {
+		os.Exit(
+			int(runBatch()),
+		)
+	}
+
 	os.Exit(
 		int(run()),
 	)
@@ -17,4 +23,5 @@ func main() {
 
 func init() {
 	container.CloudWatchClientFactory = client.New
+	container.LogsClientFactory = client.NewLogs
 }
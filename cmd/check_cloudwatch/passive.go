@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+const commandSeparators string = ";\r\n"
+
+func formatCommand(now time.Time, host string, service string, returnCode alert.ReturnCode, output string) string {
+	return fmt.Sprintf(
+		"[%d] PROCESS_SERVICE_CHECK_RESULT;%s;%s;%d;%s\n",
+		now.Unix(), host, service, int(returnCode), strings.ReplaceAll(output, "\n", `\n`),
+	)
+}
+
+func writeCommands(path string, commands []string) error {
+	log.V(3).Trace().
+		Str("package", "main").
+		Str("command_file", path).
+		Int("commands", len(commands)).
+		Msg("writing external commands")
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	for _, c := range commands {
+		if _, err := f.WriteString(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type passiveTarget struct {
+	host        string
+	service     string
+	commandFile string
+	spoolDir    string
+	icinga      icingaTarget
+}
+
+func newPassiveTarget(flags flags) *passiveTarget {
+	if !flags.isPassiveMode() {
+		return nil
+	}
+
+	log.V(3).Trace().
+		Str("package", "main").
+		Str("host", *flags.passiveHost).
+		Str("service", *flags.passiveService).
+		Str("command_file", *flags.commandFile).
+		Str("spool_dir", *flags.spoolDir).
+		Str("icinga_url", *flags.icingaURL).
+		Msg("submit passive check result")
+
+	return &passiveTarget{
+		host:        *flags.passiveHost,
+		service:     *flags.passiveService,
+		commandFile: *flags.commandFile,
+		spoolDir:    *flags.spoolDir,
+		icinga: icingaTarget{
+			url:          *flags.icingaURL,
+			user:         *flags.icingaUser,
+			passwordFile: *flags.icingaPasswordFile,
+			cert:         *flags.icingaCert,
+			key:          *flags.icingaKey,
+			ca:           *flags.icingaCA,
+			timeout:      *flags.timeout,
+		},
+	}
+}
+
+func (p passiveTarget) submit(now time.Time, returnCode alert.ReturnCode, output pluginOutput) error {
+	if p.icinga.url != "" {
+		return p.icinga.submit(now, p.host, p.service, returnCode, output)
+	}
+
+	if p.spoolDir != "" {
+		return writeCheckResult(p.spoolDir, formatCheckResult(now, p.host, p.service, returnCode, output.String()))
+	}
+
+	return writeCommands(p.commandFile, []string{formatCommand(now, p.host, p.service, returnCode, output.String())})
+}
+
+func formatCheckResult(now time.Time, host string, service string, returnCode alert.ReturnCode, output string) string {
+	return fmt.Sprintf(
+		"### Passive Check Result File ###\n"+
+			"file_time=%d\n"+
+			"\n"+
+			"### Nagios Service Check Result ###\n"+
+			"# Time: %s\n"+
+			"host_name=%s\n"+
+			"service_description=%s\n"+
+			"check_type=1\n"+
+			"check_options=0\n"+
+			"scheduled_check=0\n"+
+			"reschedule_check=0\n"+
+			"latency=0.000000\n"+
+			"start_time=%d.000000\n"+
+			"finish_time=%d.000000\n"+
+			"early_timeout=0\n"+
+			"exited_ok=1\n"+
+			"return_code=%d\n"+
+			"output=%s\n",
+		now.Unix(), now.Format(time.ANSIC), host, service, now.Unix(), now.Unix(),
+		int(returnCode), strings.ReplaceAll(output, "\n", `\n`),
+	)
+}
+
+func writeCheckResult(dir string, content string) error {
+	f, err := createCheckResultFile(dir)
+
+	if err != nil {
+		return err
+	}
+
+	log.V(3).Trace().
+		Str("package", "main").
+		Str("check_result_file", f.Name()).
+		Msg("writing check result file")
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	ok, err := os.OpenFile(f.Name()+".ok", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	return ok.Close()
+}
+
+func createCheckResultFile(dir string) (*os.File, error) {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	for range 100 {
+		name := make([]byte, 6)
+
+		for i := range name {
+			name[i] = letters[rand.IntN(len(letters))]
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, "c"+string(name)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+
+		if os.IsExist(err) {
+			continue
+		}
+
+		return f, err
+	}
+
+	return nil, fmt.Errorf("unable to create check result file in %s", dir)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		host       string
+		service    string
+		returnCode alert.ReturnCode
+		output     string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "service",
+			args: args{
+				host:       "web01",
+				service:    "CPU",
+				returnCode: alert.Critical,
+				output:     "CLOUDWATCH CRITICAL: CPUUtilization = 95 | CPUUtilization=95;80;90;;",
+			},
+			expected: "[1663582830] PROCESS_SERVICE_CHECK_RESULT;web01;CPU;2;CLOUDWATCH CRITICAL: CPUUtilization = 95 | CPUUtilization=95;80;90;;\n",
+		},
+		{
+			name: "multi-line output",
+			args: args{
+				host:       "web01",
+				service:    "CPU",
+				returnCode: alert.OK,
+				output:     "CLOUDWATCH OK: a\nb",
+			},
+			expected: "[1663582830] PROCESS_SERVICE_CHECK_RESULT;web01;CPU;0;CLOUDWATCH OK: a\\nb\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(
+				tc.expected,
+				formatCommand(now, tc.args.host, tc.args.service, tc.args.returnCode, tc.args.output),
+				"command",
+			)
+		})
+	}
+}
+
+func Test_writeCommands(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     string
+		expected bool
+	}
+
+	dir := t.TempDir()
+
+	commandFile := filepath.Join(dir, "nagios.cmd")
+
+	if err := os.WriteFile(commandFile, []byte("[0] EXISTING\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []testCase{
+		{
+			name:     "command file",
+			args:     commandFile,
+			expected: true,
+		},
+		{
+			name:     "missing command file",
+			args:     filepath.Join(dir, "UNKNOWN"),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			err := writeCommands(tc.args, []string{"[1] A\n", "[2] B\n"})
+
+			if !tc.expected {
+				assert.NotNil(err, "is error")
+
+				return
+			}
+
+			assert.Nil(err, "is not error")
+
+			b, _ := os.ReadFile(tc.args)
+
+			assert.Equal("[0] EXISTING\n[1] A\n[2] B\n", string(b), "content")
+		})
+	}
+}
+
+func Test_formatCheckResult(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	expected := "" +
+		"### Passive Check Result File ###\n" +
+		"file_time=1663582830\n" +
+		"\n" +
+		"### Nagios Service Check Result ###\n" +
+		"# Time: Mon Sep 19 10:20:30 2022\n" +
+		"host_name=web01\n" +
+		"service_description=CPU\n" +
+		"check_type=1\n" +
+		"check_options=0\n" +
+		"scheduled_check=0\n" +
+		"reschedule_check=0\n" +
+		"latency=0.000000\n" +
+		"start_time=1663582830.000000\n" +
+		"finish_time=1663582830.000000\n" +
+		"early_timeout=0\n" +
+		"exited_ok=1\n" +
+		"return_code=1\n" +
+		"output=CLOUDWATCH WARNING: a\\nb\n"
+
+	assert.Equal(expected, formatCheckResult(now, "web01", "CPU", alert.Warning, "CLOUDWATCH WARNING: a\nb"), "check result")
+}
+
+func Test_passiveTarget_submit(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     func(dir string) passiveTarget
+		expected []string
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "command file",
+			args: func(dir string) passiveTarget {
+				commandFile := filepath.Join(dir, "nagios.cmd")
+
+				if err := os.WriteFile(commandFile, []byte{}, 0o600); err != nil {
+					t.Fatal(err)
+				}
+
+				return passiveTarget{host: "web01", service: "CPU", commandFile: commandFile}
+			},
+			expected: []string{"nagios.cmd"},
+		},
+		{
+			name: "spool directory",
+			args: func(dir string) passiveTarget {
+				return passiveTarget{host: "web01", service: "CPU", spoolDir: dir}
+			},
+			expected: []string{"c??????", "c??????.ok"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			dir := t.TempDir()
+
+			err := tc.args(dir).submit(now, alert.OK, pluginOutput{text: "CLOUDWATCH OK: ok"})
+
+			assert.Nil(err, "is not error")
+
+			entries, _ := os.ReadDir(dir)
+
+			assert.Len(entries, len(tc.expected), "files")
+
+			for i, e := range entries {
+				matched, _ := filepath.Match(tc.expected[i], e.Name())
+
+				assert.True(matched, e.Name())
+			}
+
+			b, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+
+			assert.Contains(string(b), "web01", "host")
+		})
+	}
+}
+
+func Test_summary_print_passive(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	dir := t.TempDir()
+
+	s := newSummary(formatClassic, 0)
+
+	s.passive = &passiveTarget{host: "web01", service: "CPU", commandFile: filepath.Join(dir, "nagios.cmd")}
+
+	ci := make(chan bool)
+
+	co := captureStdout(t, ci)
+
+	returnCode := s.print(alert.OK, "ok")
+
+	ci <- true
+
+	output := <-co
+
+	assert.Equal(alert.Unknown, returnCode, "alert.ReturnCode")
+
+	assert.Regexp(`^CLOUDWATCH UNKNOWN: unable to submit check result: `, output, "output message")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/container"
+)
+
+func setupRecording(flags flags) (time.Time, error) {
+	switch {
+	case *flags.replay != "":
+		recording, err := client.LoadRecording(*flags.replay)
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		container.CloudWatchClientFactory = client.NewReplayer(recording)
+
+		return recording.Time, nil
+	case *flags.record != "":
+		container.CloudWatchClientFactory = client.NewRecorder(*flags.record, container.CloudWatchClientFactory)
+	}
+
+	return time.Now(), nil
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
 )
 
@@ -13,54 +17,312 @@ func run() alert.ReturnCode {
 
 	log.SetVerbosity(*flags.verbosity)
 
-	summary := newSummary(*flags.classicOutput, *flags.verbosity)
+	summary := newSummary(flags.summaryFormat(), *flags.verbosity)
 
 	if err != nil {
-		summary.print(alert.Unknown, err.Error())
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	summary.serviceName = *flags.serviceName
+
+	if *flags.outputTemplate != "" {
+		t, err := parseOutputTemplate(*flags.outputTemplate)
 
-		return alert.Unknown
+		if err != nil {
+			return summary.print(alert.Unknown, err.Error())
+		}
+
+		summary.template = t
 	}
 
-	checker, err := alert.NewChecker(*flags.warnRange, *flags.criticalRange, *flags.datapointsThreshold)
+	summary.passive = newPassiveTarget(flags)
+	summary.longOutput = *flags.longOutput
+
+	now, err := setupRecording(flags)
 
 	if err != nil {
-		summary.print(alert.Unknown, err.Error())
+		return summary.print(alert.Unknown, err.Error())
+	}
 
-		return alert.Unknown
+	if flags.isAlarmMode() {
+		return runAlarms(flags, summary, now)
 	}
 
-	client, err := cloudwatch.New(*flags.duration, *flags.queries, *flags.timeout)
+	checker, err := alert.NewChecker(*flags.warnRange, *flags.criticalRange, *flags.datapointsThreshold, *flags.missingData)
 
 	if err != nil {
-		summary.print(alert.Unknown, err.Error())
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	if flags.isLogsMode() {
+		return runInsights(flags, summary, checker, now)
+	}
+
+	checkers := map[string]alert.Checker{}
+
+	for _, spec := range *flags.thresholds {
+		id, c, err := alert.NewQueryChecker(spec, *flags.datapointsThreshold, *flags.missingData)
+
+		if err != nil {
+			return summary.print(alert.Unknown, err.Error())
+		}
 
-		return alert.Unknown
+		if _, exists := checkers[id]; exists {
+			err := errors.NewArgumentErrorWithMessage("threshold for the query is specified more than once", "threshold", spec)
+
+			return summary.print(alert.Unknown, err.Error())
+		}
+
+		checkers[id] = c
+	}
+
+	metric := cloudwatch.Metric{
+		Namespace:  *flags.namespace,
+		MetricName: *flags.metricName,
+		Dimensions: *flags.dimensions,
+		Stat:       *flags.stat,
+		Period:     *flags.period,
+		SQL:        *flags.sql,
+	}
+
+	if *flags.dryRun {
+		input, err := cloudwatch.BuildInput(*flags.duration, *flags.queries, *flags.vars, metric, now)
+
+		if err == nil {
+			err = printDryRun(input, checker, checkers, *flags.missingData)
+		}
+
+		if err != nil {
+			return summary.print(alert.Unknown, err.Error())
+		}
+
+		return alert.OK
 	}
 
-	values, err := client.GetMetricValues(time.Now())
+	client, err := cloudwatch.New(
+		*flags.duration, *flags.queries, *flags.vars,
+		metric,
+		newOptions(flags),
+		*flags.timeout,
+	)
 
 	if err != nil {
-		summary.print(alert.Unknown, err.Error())
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	series, err := client.GetMetricSeries(now)
 
-		return alert.Unknown
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
 	}
 
-	returnCode, err := checker.CheckStatus(values)
+	duration := time.Duration(*flags.duration) * time.Minute
+
+	if *flags.allSeries || *flags.sql != "" || len(checkers) != 0 {
+		returnCode, results := checkSeries(checker, checkers, series, now, duration)
+
+		return summary.printOutput(
+			returnCode,
+			summary.appendSeriesDatapoints(
+				summary.annotate(
+					summary.buildSeries(results, len(checkers) != 0, *flags.top),
+					client.Warnings(),
+				),
+				results,
+			),
+		)
+	}
+
+	returnCode, err := checker.CheckTimeSeries(
+		series[0].Timestamps, series[0].Values,
+		now, time.Duration(series[0].Period)*time.Second, duration,
+	)
 
 	if err != nil {
-		summary.print(returnCode, err.Error())
+		returnCode = summary.print(returnCode, err.Error())
 	} else {
 		a1, a2, a3 := client.LatestValue()
 		b1, b2, b3, b4 := checker.Result()
 
-		summary.print(
+		perfdata := []perfdataEntry{}
+
+		for _, s := range series[1:] {
+			if len(s.Values) == 0 {
+				continue
+			}
+
+			value, _ := s.Latest()
+
+			perfdata = append(perfdata, newPerfdata(s.Name(), s.Unit, value, "", ""))
+		}
+
+		returnCode = summary.printOutput(
+			returnCode,
+			summary.appendDatapoints(
+				summary.annotate(
+					summary.appendPerfdata(
+						summary.build(
+							*flags.warnRange, *flags.criticalRange, *flags.datapointsThreshold,
+							a1, series[0].Unit, a2, a3, b1, b2, b3, b4,
+						),
+						perfdata,
+					),
+					client.Warnings(),
+				),
+				checker.Datapoints(),
+			),
+		)
+	}
+
+	return returnCode
+}
+
+func runAlarms(flags flags, summary summary, now time.Time) alert.ReturnCode {
+	alarms, err := cloudwatch.NewAlarms(*flags.alarmNames, *flags.alarmPrefix, newOptions(flags), *flags.timeout)
+
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	described, err := alarms.Describe(now)
+
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	returnCode := alert.OK
+
+	results := make([]alarmResult, 0, len(described))
+
+	for _, a := range described {
+		r := alert.AlarmState(a.State)
+
+		results = append(results, alarmResult{
+			name:        a.Name,
+			composite:   a.Composite,
+			state:       a.State,
+			stateReason: a.StateReason,
+			updatedAt:   a.UpdatedAt,
+			returnCode:  r,
+		})
+
+		returnCode = alert.Worst(returnCode, r)
+	}
+
+	return summary.printOutput(returnCode, summary.buildAlarms(results))
+}
+
+func runInsights(flags flags, summary summary, checker alert.Checker, now time.Time) alert.ReturnCode {
+	insights, err := cloudwatch.NewInsights(
+		*flags.duration, *flags.logGroups, *flags.logsQuery, *flags.field, *flags.emptyValue,
+		newOptions(flags),
+		*flags.timeout,
+	)
+
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	values, err := insights.GetValues(now)
+
+	if err != nil {
+		return summary.print(alert.Unknown, err.Error())
+	}
+
+	returnCode, err := checker.CheckStatus(values)
+
+	if err != nil {
+		returnCode = summary.print(returnCode, err.Error())
+	} else {
+		b1, b2, b3, b4 := checker.Result()
+
+		returnCode = summary.printOutput(
 			returnCode,
-			summary.build(
-				*flags.warnRange, *flags.criticalRange, *flags.datapointsThreshold,
-				a1, a2, a3, b1, b2, b3, b4,
+			summary.appendDatapoints(
+				summary.build(
+					*flags.warnRange, *flags.criticalRange, *flags.datapointsThreshold,
+					insights.Field(), "", values[0], now, b1, b2, b3, b4,
+				),
+				checker.Datapoints(),
 			),
 		)
 	}
 
 	return returnCode
 }
+
+func newOptions(flags flags) types.Options {
+	return types.Options{
+		Region:          *flags.region,
+		Profile:         *flags.profile,
+		EndpointURL:     *flags.endpointURL,
+		RoleARN:         *flags.roleARN,
+		ExternalID:      *flags.externalID,
+		RoleSessionName: *flags.roleSessionName,
+		Retries:         *flags.retries,
+		Timeout:         *flags.timeout,
+		CacheDir:        *flags.cacheDir,
+		CacheTTL:        *flags.cacheTTL,
+	}
+}
+
+func checkSeries(
+	checker alert.Checker, checkers map[string]alert.Checker,
+	series []cloudwatch.Series, now time.Time, duration time.Duration,
+) (alert.ReturnCode, []seriesResult) {
+	returnCode := alert.OK
+
+	results := make([]seriesResult, 0, len(series))
+
+	matched := map[string]bool{}
+
+	for _, s := range series {
+		c, ok := checkers[s.Id]
+
+		if ok {
+			matched[s.Id] = true
+		} else {
+			c = checker
+		}
+
+		r, err := c.CheckTimeSeries(s.Timestamps, s.Values, now, time.Duration(s.Period)*time.Second, duration)
+
+		_, _, outOfWarnRange, outOfCriticalRange := c.Result()
+		warnRange, criticalRange, datapointsThreshold := c.Thresholds()
+
+		value, timestamp := s.Latest()
+
+		results = append(results, seriesResult{
+			metricName:          s.Name(),
+			unit:                s.Unit,
+			value:               value,
+			timestamp:           timestamp,
+			returnCode:          r,
+			err:                 err,
+			warnRange:           warnRange,
+			criticalRange:       criticalRange,
+			datapointsThreshold: datapointsThreshold,
+			outOfWarnRange:      outOfWarnRange,
+			outOfCriticalRange:  outOfCriticalRange,
+			datapoints:          c.Datapoints(),
+		})
+
+		returnCode = alert.Worst(returnCode, r)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(checkers)) {
+		if matched[id] {
+			continue
+		}
+
+		results = append(results, seriesResult{
+			metricName: id,
+			returnCode: alert.Unknown,
+			err:        errors.NewArgumentErrorWithMessage("query threshold matches no returned series", "threshold", id),
+		})
+
+		returnCode = alert.Worst(returnCode, alert.Unknown)
+	}
+
+	return returnCode, results
+}
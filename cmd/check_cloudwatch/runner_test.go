@@ -2,12 +2,15 @@ package main
 
 import (
 	goerrors "errors"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
@@ -22,7 +25,8 @@ func Test_run(t *testing.T) {
 
 	type args struct {
 		commandArgs             []string
-		cloudwatchClientFactory func() (types.Client, error)
+		cloudwatchClientFactory func(types.Options) (types.Client, error)
+		logsClientFactory       func(types.Options) (types.LogsClient, error)
 	}
 
 	type testCase struct {
@@ -45,7 +49,7 @@ func Test_run(t *testing.T) {
 					"--queries",
 					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
 				},
-				cloudwatchClientFactory: func() (types.Client, error) {
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					output := &cloudwatch.GetMetricDataOutput{
@@ -82,7 +86,7 @@ func Test_run(t *testing.T) {
 					"--queries",
 					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
 				},
-				cloudwatchClientFactory: func() (types.Client, error) {
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					output := &cloudwatch.GetMetricDataOutput{
@@ -119,7 +123,7 @@ func Test_run(t *testing.T) {
 					"--queries",
 					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
 				},
-				cloudwatchClientFactory: func() (types.Client, error) {
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					output := &cloudwatch.GetMetricDataOutput{
@@ -143,6 +147,657 @@ func Test_run(t *testing.T) {
 			},
 			expected: alert.Critical,
 		},
+		{
+			name: "all series",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0.0:1.5",
+					"--critical",
+					"0.0:2.5",
+					"--datapoints",
+					"1/1",
+					"--queries",
+					`[{"Id":"e1","Expression":"SEARCH('{AWS/EC2,InstanceId} CPUUtilization', 'Average')"}]`,
+					"--all-series",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id:    aws.String("e1"),
+								Label: aws.String("i-1"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+							{
+								Id:    aws.String("e1"),
+								Label: aws.String("i-2"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									2.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Warning,
+		},
+		{
+			name: "all series API error",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0.0:1.5",
+					"--critical",
+					"0.0:2.5",
+					"--datapoints",
+					"1/1",
+					"--queries",
+					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
+					"--all-series",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, goerrors.New(""))
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "missing data breaching",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0.0:1.5",
+					"--critical",
+					"0.0:2.5",
+					"--datapoints",
+					"2/2",
+					"--missing-data",
+					"breaching",
+					"--queries",
+					`[{"Id":"e1","Expression":"TIME_SERIES(1)","Period":60}]`,
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("e1"),
+								Timestamps: []time.Time{
+									time.Now().Truncate(time.Minute).Add(-1 * time.Minute),
+								},
+								Values: []float64{
+									2.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Warning,
+		},
+		{
+			name: "invalid missing data",
+			args: args{
+				commandArgs: []string{
+					"--missing-data",
+					"a",
+					"--queries",
+					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
+				},
+				cloudwatchClientFactory: client.New,
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "invalid output template",
+			args: args{
+				commandArgs: []string{
+					"--output-template",
+					"{{.Unknown}}",
+					"--queries",
+					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
+				},
+				cloudwatchClientFactory: client.New,
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "query thresholds",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0.0:1.5",
+					"--critical",
+					"0.0:2.5",
+					"--threshold",
+					"mem=@0.0:1.0,@0.0:0.5,1/1",
+					"--queries",
+					`[{"Id":"cpu","Expression":"TIME_SERIES(1)"},{"Id":"mem","Expression":"TIME_SERIES(0.1)"}]`,
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("cpu"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+							{
+								Id: aws.String("mem"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									0.1,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Critical,
+		},
+		{
+			name: "unmatched query threshold",
+			args: args{
+				commandArgs: []string{
+					"--threshold",
+					"cpuu=80,90",
+					"--queries",
+					`[{"Id":"cpu","Expression":"TIME_SERIES(1)"},{"Id":"m1","Expression":"TIME_SERIES(2)","ReturnData":false}]`,
+					"--threshold",
+					"m1=80,90",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("cpu"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "unmatched query threshold with critical series",
+			args: args{
+				commandArgs: []string{
+					"--threshold",
+					"cpu=0.0:0.5,0.0:0.8,1/1",
+					"--threshold",
+					"cpuu=80,90",
+					"--queries",
+					`[{"Id":"cpu","Expression":"TIME_SERIES(1)"}]`,
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("cpu"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Critical,
+		},
+		{
+			name: "duplicate query thresholds",
+			args: args{
+				commandArgs: []string{
+					"--threshold",
+					"cpu=80,90",
+					"--threshold",
+					"cpu=70,90",
+					"--queries",
+					`[{"Id":"cpu","Expression":"TIME_SERIES(1)"}]`,
+				},
+				cloudwatchClientFactory: client.New,
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "unresolved query variable",
+			args: args{
+				commandArgs: []string{
+					"--var",
+					"VolumeId=vol-123",
+					"--queries",
+					`[{"Id":"e1","Label":"{{.InstanceId}}","Expression":"TIME_SERIES(1)"}]`,
+				},
+				cloudwatchClientFactory: client.New,
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "metric",
+			args: args{
+				commandArgs: []string{
+					"--namespace",
+					"AWS/EC2",
+					"--metric",
+					"CPUUtilization",
+					"--dimension",
+					"InstanceId=i-123",
+					"--warning",
+					"80",
+					"--critical",
+					"90",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id:    aws.String("m1"),
+								Label: aws.String("CPUUtilization"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									85.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.MatchedBy(func(input *cloudwatch.GetMetricDataInput) bool {
+						return aws.ToString(input.MetricDataQueries[0].MetricStat.Metric.MetricName) == "CPUUtilization"
+					})).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Warning,
+		},
+		{
+			name: "sql",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"5",
+					"--critical",
+					"10",
+					"--sql",
+					`SELECT AVG(Errors) FROM SCHEMA("AWS/Lambda", FunctionName) GROUP BY FunctionName`,
+					"--period",
+					"60",
+					"--top",
+					"1",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id:    aws.String("q1"),
+								Label: aws.String("fn-a"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+							{
+								Id:    aws.String("q1"),
+								Label: aws.String("fn-b"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC),
+								},
+								Values: []float64{
+									7.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Warning,
+		},
+		{
+			name: "alarm",
+			args: args{
+				commandArgs: []string{
+					"--alarm-name",
+					"web-cpu",
+					"--alarm-name",
+					"web-health",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.DescribeAlarmsOutput{
+						MetricAlarms: []awstypes.MetricAlarm{
+							{
+								AlarmName:   aws.String("web-cpu"),
+								StateValue:  awstypes.StateValueOk,
+								StateReason: aws.String("Threshold Crossed"),
+							},
+						},
+						CompositeAlarms: []awstypes.CompositeAlarm{
+							{
+								AlarmName:   aws.String("web-health"),
+								StateValue:  awstypes.StateValueAlarm,
+								StateReason: aws.String("web-memory transitioned to ALARM"),
+							},
+						},
+					}
+
+					m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Critical,
+		},
+		{
+			name: "alarm insufficient data",
+			args: args{
+				commandArgs: []string{
+					"--alarm-prefix",
+					"web-",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.DescribeAlarmsOutput{
+						MetricAlarms: []awstypes.MetricAlarm{
+							{
+								AlarmName:  aws.String("web-cpu"),
+								StateValue: awstypes.StateValueInsufficientData,
+							},
+						},
+					}
+
+					m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "alarm API error",
+			args: args{
+				commandArgs: []string{
+					"--alarm-name",
+					"web-cpu",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.DescribeAlarmsOutput{}, goerrors.New(""))
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "alarm client error",
+			args: args{
+				commandArgs: []string{
+					"--alarm-name",
+					"web-cpu",
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					return nil, goerrors.New("")
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "logs query",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0:10",
+					"--critical",
+					"0:20",
+					"--log-group",
+					"/app/web",
+					"--logs-query",
+					"filter @message like /ERROR/ | stats count(*) as errors",
+				},
+				logsClientFactory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							{
+								{
+									Field: aws.String("errors"),
+									Value: aws.String("15"),
+								},
+							},
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(&cloudwatchlogs.StartQueryOutput{QueryId: aws.String("q1")}, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Warning,
+		},
+		{
+			name: "logs query insufficient results",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0:10",
+					"--datapoints",
+					"2/2",
+					"--log-group",
+					"/app/web",
+					"--logs-query",
+					"stats count(*) as errors",
+				},
+				logsClientFactory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							{
+								{
+									Field: aws.String("errors"),
+									Value: aws.String("1"),
+								},
+							},
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(&cloudwatchlogs.StartQueryOutput{QueryId: aws.String("q1")}, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "logs query empty value",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0:10",
+					"--log-group",
+					"/app/web",
+					"--logs-query",
+					"filter @message like /ERROR/ | stats count(*) as errors",
+					"--field",
+					"errors",
+					"--empty-value",
+					"0",
+				},
+				logsClientFactory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(&cloudwatchlogs.StartQueryOutput{QueryId: aws.String("q1")}, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.OK,
+		},
+		{
+			name: "logs query API error",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0:10",
+					"--log-group",
+					"/app/web",
+					"--logs-query",
+					"stats count(*) as errors",
+				},
+				logsClientFactory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(&cloudwatchlogs.StartQueryOutput{}, goerrors.New(""))
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "logs client error",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0:10",
+					"--log-group",
+					"/app/web",
+					"--logs-query",
+					"stats count(*) as errors",
+				},
+				logsClientFactory: func(types.Options) (types.LogsClient, error) {
+					return nil, goerrors.New("")
+				},
+			},
+			expected: alert.Unknown,
+		},
+		{
+			name: "passive submission failure",
+			args: args{
+				commandArgs: []string{
+					"--warning",
+					"0.0:1.5",
+					"--critical",
+					"0.0:2.5",
+					"--datapoints",
+					"1/1",
+					"--queries",
+					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
+					"--passive-host",
+					"web01",
+					"--passive-service",
+					"CPU",
+					"--command-file",
+					filepath.Join(t.TempDir(), "nagios.cmd"),
+				},
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("e1"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: alert.Unknown,
+		},
 		{
 			name: "invalid args",
 			args: args{
@@ -200,7 +855,7 @@ func Test_run(t *testing.T) {
 					"--queries",
 					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
 				},
-				cloudwatchClientFactory: func() (types.Client, error) {
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					output := &cloudwatch.GetMetricDataOutput{}
@@ -225,7 +880,7 @@ func Test_run(t *testing.T) {
 					"--queries",
 					`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
 				},
-				cloudwatchClientFactory: func() (types.Client, error) {
+				cloudwatchClientFactory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					output := &cloudwatch.GetMetricDataOutput{
@@ -252,8 +907,71 @@ func Test_run(t *testing.T) {
 			helper.SetLogOutputDiscard(t)
 			helper.SetCommandArgs(t, tc.args.commandArgs)
 			helper.SetCloudWatchClientFactory(t, tc.args.cloudwatchClientFactory)
+			helper.SetLogsClientFactory(t, tc.args.logsClientFactory)
 
 			assert.Equal(tc.expected, run(), "alert.ReturnCode")
 		})
 	}
 }
+
+func Test_run_recordAndReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+
+	commandArgs := []string{
+		"--warning",
+		"0.0:1.5",
+		"--critical",
+		"0.0:2.5",
+		"--queries",
+		`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
+	}
+
+	t.Run("record", func(t *testing.T) {
+		helper.SetLogOutputDiscard(t)
+		helper.SetCommandArgs(t, append(commandArgs, "--record", path))
+		helper.SetCloudWatchClientFactory(t, func(types.Options) (types.Client, error) {
+			m := &mock.CloudWatchClient{}
+
+			output := &cloudwatch.GetMetricDataOutput{
+				MetricDataResults: []awstypes.MetricDataResult{
+					{
+						Id: aws.String("e1"),
+						Timestamps: []time.Time{
+							time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC),
+						},
+						Values: []float64{
+							3.0,
+						},
+					},
+				},
+			}
+
+			m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil).Once()
+
+			return m, nil
+		})
+
+		assert.Equal(alert.Critical, run(), "alert.ReturnCode")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		helper.SetLogOutputDiscard(t)
+		helper.SetCommandArgs(t, append(commandArgs, "--replay", path))
+		helper.SetCloudWatchClientFactory(t, func(types.Options) (types.Client, error) {
+			t.Error("API client must not be created")
+
+			return nil, goerrors.New("")
+		})
+
+		assert.Equal(alert.Critical, run(), "alert.ReturnCode")
+	})
+
+	t.Run("missing recording", func(t *testing.T) {
+		helper.SetLogOutputDiscard(t)
+		helper.SetCommandArgs(t, append(commandArgs, "--replay", path+".missing"))
+
+		assert.Equal(alert.Unknown, run(), "alert.ReturnCode")
+	})
+}
@@ -2,6 +2,11 @@ package main
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
 	"time"
 
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/alert"
@@ -9,66 +14,479 @@ import (
 )
 
 type summary struct {
-	classicOutput bool
-	isVerbose     bool
+	format      outputFormat
+	isVerbose   bool
+	longOutput  bool
+	template    *template.Template
+	serviceName string
+	passive     *passiveTarget
 }
 
-const pluginName string = "CLOUDWATCH"
+type seriesResult struct {
+	metricName          string
+	unit                string
+	value               float64
+	timestamp           time.Time
+	returnCode          alert.ReturnCode
+	err                 error
+	warnRange           string
+	criticalRange       string
+	datapointsThreshold string
+	outOfWarnRange      int
+	outOfCriticalRange  int
+	datapoints          []alert.Datapoint
+}
+
+type pluginOutput struct {
+	text       string
+	perfdata   []perfdataEntry
+	longOutput string
+}
 
-func newSummary(classicOutput bool, verbosity int) summary {
+type perfdataEntry struct {
+	label  string
+	value  float64
+	uom    string
+	levels []string
+}
+
+type alarmResult struct {
+	name        string
+	composite   bool
+	state       string
+	stateReason string
+	updatedAt   time.Time
+	returnCode  alert.ReturnCode
+}
+
+const defaultServiceName string = "CLOUDWATCH"
+
+var unitsOfMeasure = map[string]string{
+	"Percent":      "%",
+	"Seconds":      "s",
+	"Milliseconds": "ms",
+	"Microseconds": "us",
+	"Bytes":        "B",
+	"Kilobytes":    "KB",
+	"Megabytes":    "MB",
+	"Gigabytes":    "GB",
+	"Terabytes":    "TB",
+	"Count":        "c",
+}
+
+func newSummary(format outputFormat, verbosity int) summary {
 	log.V(3).Trace().
 		Str("package", "main").
-		Bool("classic_output", classicOutput).
+		Str("output_format", format.String()).
 		Int("verbosity", verbosity).
 		Msg("set output options")
 
 	return summary{
-		classicOutput: classicOutput,
-		isVerbose:     1 <= verbosity,
+		format:      format,
+		isVerbose:   1 <= verbosity,
+		template:    newOutputTemplate(verbosity),
+		serviceName: defaultServiceName,
 	}
 }
 
-func (o summary) print(returnCode alert.ReturnCode, msg string) {
-	if o.classicOutput {
-		fmt.Printf("%s %s: %s\n", pluginName, returnCode.String(), msg)
-	} else {
+func (o summary) print(returnCode alert.ReturnCode, msg string) alert.ReturnCode {
+	return o.printOutput(returnCode, pluginOutput{text: msg})
+}
+
+func (o summary) printOutput(returnCode alert.ReturnCode, output pluginOutput) alert.ReturnCode {
+	now := time.Now()
+
+	if o.passive != nil {
+		err := o.passive.submit(now, returnCode, o.withStatus(returnCode, output))
+
+		if err == nil {
+			return returnCode
+		}
+
+		returnCode, output = alert.Unknown, pluginOutput{text: fmt.Sprintf("unable to submit check result: %s", err)}
+	}
+
+	switch o.format {
+	case formatClassic:
+		fmt.Println(o.line(returnCode, output))
+	case formatCheckmk:
+		fmt.Println(formatLocalCheck(o.serviceName, returnCode, output))
+	case formatSensu:
+		s, err := formatSensuCheckResult(now, o.serviceName, returnCode, o.line(returnCode, output), output.perfdata)
+
+		if err != nil {
+			s = o.line(alert.Unknown, pluginOutput{text: err.Error()})
+		}
+
+		fmt.Println(s)
+	default:
 		log.V(0).Info().
-			Str("service", pluginName).
+			Str("service", o.serviceName).
 			Str("status", returnCode.String()).
-			Msg(msg)
+			Msg(output.String())
+	}
+
+	return returnCode
+}
+
+func (o summary) line(returnCode alert.ReturnCode, output pluginOutput) string {
+	return o.withStatus(returnCode, output).String()
+}
+
+func (o summary) withStatus(returnCode alert.ReturnCode, output pluginOutput) pluginOutput {
+	output.text = fmt.Sprintf("%s %s: %s", o.serviceName, returnCode.String(), sanitizeText(output.text))
+
+	return output
+}
+
+func (p pluginOutput) String() string {
+	s := p.text
+
+	if len(p.perfdata) != 0 {
+		perfdata := make([]string, 0, len(p.perfdata))
+
+		for _, e := range p.perfdata {
+			perfdata = append(perfdata, e.String())
+		}
+
+		s += " | " + strings.Join(perfdata, " ")
+	}
+
+	if p.longOutput != "" {
+		s += "\n" + p.longOutput
 	}
+
+	return s
 }
 
 func (o summary) build(
 	warnRange string, criticalRange string, datapointsThreshold string,
-	metricName string, value float64, timestamp time.Time,
+	metricName string, unit string, value float64, timestamp time.Time,
 	isWarn bool, isCritical bool, outOfWarnRange int, outOfCriticalRange int,
-) string {
-	if o.isVerbose {
-		return fmt.Sprintf(
-			"%s = %g @ %s; above thresholds [warn,crit] = %d,%d; threshold = %s | value=%g;%s;%s;; datapoints_warn=%d;%s;;; datapoints_crit=%d;;%s;;",
-			metricName, value, timestamp, outOfWarnRange, outOfCriticalRange, datapointsThreshold,
-			value, warnRange, criticalRange, outOfWarnRange, datapointsThreshold, outOfCriticalRange, datapointsThreshold,
-		)
+) pluginOutput {
+	status := alert.OK
+
+	if isCritical {
+		status = alert.Critical
+	} else if isWarn {
+		status = alert.Warning
+	}
+
+	perfdata := []perfdataEntry{newPerfdata(metricName, unit, value, warnRange, criticalRange)}
+
+	if o.isVerbose || (isWarn && !isCritical) {
+		perfdata = append(perfdata, perfdataEntry{
+			label:  "datapoints_warn",
+			value:  float64(outOfWarnRange),
+			levels: []string{datapointsThreshold, "", "", ""},
+		})
+	}
+
+	if o.isVerbose || isCritical {
+		perfdata = append(perfdata, perfdataEntry{
+			label:  "datapoints_crit",
+			value:  float64(outOfCriticalRange),
+			levels: []string{"", datapointsThreshold, "", ""},
+		})
+	}
+
+	var b strings.Builder
+
+	err := o.template.Execute(&b, outputData{
+		ServiceName:         o.serviceName,
+		MetricName:          metricName,
+		Unit:                unit,
+		Value:               value,
+		Timestamp:           timestamp,
+		Status:              status.String(),
+		WarnRange:           warnRange,
+		CriticalRange:       criticalRange,
+		DatapointsThreshold: datapointsThreshold,
+		IsWarn:              isWarn,
+		IsCritical:          isCritical,
+		OutOfWarnRange:      outOfWarnRange,
+		OutOfCriticalRange:  outOfCriticalRange,
+		Perfdata:            perfdata[0].String(),
+	})
+
+	if err != nil {
+		return pluginOutput{
+			text:     fmt.Sprintf("unable to render output template: %s", err),
+			perfdata: perfdata,
+		}
+	}
+
+	return pluginOutput{
+		text:     sanitizeText(b.String()),
+		perfdata: perfdata,
+	}
+}
+
+func (o summary) appendPerfdata(output pluginOutput, perfdata []perfdataEntry) pluginOutput {
+	output.perfdata = append(output.perfdata, perfdata...)
+
+	return output
+}
+
+func (o summary) buildSeries(results []seriesResult, listAll bool, top int) pluginOutput {
+	messages := []string{}
+	perfdata := []perfdataEntry{}
+
+	offenders := []seriesResult{}
+
+	for _, r := range results {
+		if r.returnCode != alert.OK {
+			offenders = append(offenders, r)
+		}
+
+		if r.err == nil {
+			perfdata = append(perfdata, newPerfdata(r.metricName, r.unit, r.value, r.warnRange, r.criticalRange))
+		}
+
+		switch {
+		case o.isVerbose && r.err != nil:
+			messages = append(messages, fmt.Sprintf("%s: %s (%s)", r.metricName, r.err, r.returnCode))
+		case o.isVerbose:
+			messages = append(messages, fmt.Sprintf(
+				"%s = %g @ %s; above thresholds [warn,crit] = %d,%d; threshold = %s (%s)",
+				r.metricName, r.value, r.timestamp, r.outOfWarnRange, r.outOfCriticalRange, r.datapointsThreshold, r.returnCode,
+			))
+		case listAll || r.returnCode != alert.OK:
+			messages = append(messages, seriesMessage(r))
+		}
+	}
+
+	if !o.isVerbose && !listAll && 0 < top && top < len(offenders) {
+		messages = topOffenders(offenders, top)
+	}
+
+	var msg string
+
+	if o.isVerbose || listAll {
+		msg = strings.Join(messages, ", ")
+	} else if len(offenders) == 0 {
+		msg = fmt.Sprintf("%d series within thresholds", len(results))
 	} else {
-		if isCritical {
-			return fmt.Sprintf(
-				"%s = %g; above thresholds = %d | value=%g;%s;%s;; datapoints_crit=%d;;%s;;",
-				metricName, value, outOfCriticalRange,
-				value, warnRange, criticalRange, outOfCriticalRange, datapointsThreshold,
-			)
-		} else if isWarn {
-			return fmt.Sprintf(
-				"%s = %g; above thresholds = %d | value=%g;%s;%s;; datapoints_warn=%d;%s;;;",
-				metricName, value, outOfWarnRange,
-				value, warnRange, criticalRange, outOfWarnRange, datapointsThreshold,
-			)
-		} else {
-			return fmt.Sprintf(
-				"%s = %g | value=%g;%s;%s;;",
-				metricName, value,
-				value, warnRange, criticalRange,
-			)
+		msg = fmt.Sprintf("%d of %d series above thresholds: %s", len(offenders), len(results), strings.Join(messages, ", "))
+	}
+
+	return pluginOutput{
+		text:     msg,
+		perfdata: perfdata,
+	}
+}
+
+func seriesMessage(r seriesResult) string {
+	if r.err != nil {
+		return fmt.Sprintf("%s (%s)", r.metricName, r.returnCode)
+	}
+
+	return fmt.Sprintf("%s = %g (%s)", r.metricName, r.value, r.returnCode)
+}
+
+func topOffenders(offenders []seriesResult, top int) []string {
+	ranked := slices.Clone(offenders)
+
+	slices.SortStableFunc(ranked, func(a, b seriesResult) int {
+		if a.returnCode != b.returnCode {
+			if alert.Worst(a.returnCode, b.returnCode) == a.returnCode {
+				return -1
+			}
+
+			return 1
+		}
+
+		switch {
+		case a.err != nil || b.err != nil:
+			return 0
+		case a.value > b.value:
+			return -1
+		case a.value < b.value:
+			return 1
+		default:
+			return 0
 		}
+	})
+
+	messages := make([]string, 0, top+1)
+
+	for _, r := range ranked[:top] {
+		messages = append(messages, seriesMessage(r))
 	}
+
+	return append(messages, fmt.Sprintf("and %d more", len(ranked)-top))
+}
+
+func (o summary) buildAlarms(results []alarmResult) pluginOutput {
+	messages := []string{}
+
+	counts := map[string]int{}
+
+	unhealthy := 0
+
+	for _, r := range results {
+		counts[r.state]++
+
+		if r.returnCode != alert.OK {
+			unhealthy++
+		}
+
+		name := r.name
+
+		if r.composite {
+			name += " (composite)"
+		}
+
+		switch {
+		case o.isVerbose:
+			messages = append(messages, fmt.Sprintf(
+				"%s is %s @ %s: %s (%s)",
+				name, r.state, r.updatedAt, sanitizeText(r.stateReason), r.returnCode,
+			))
+		case len(results) == 1 || r.returnCode != alert.OK:
+			messages = append(messages, fmt.Sprintf("%s is %s: %s", name, r.state, sanitizeText(r.stateReason)))
+		}
+	}
+
+	var msg string
+
+	if o.isVerbose || len(results) == 1 {
+		msg = strings.Join(messages, ", ")
+	} else if unhealthy == 0 {
+		msg = fmt.Sprintf("%d alarms in OK state", len(results))
+	} else {
+		msg = fmt.Sprintf("%d of %d alarms not in OK state: %s", unhealthy, len(results), strings.Join(messages, ", "))
+	}
+
+	perfdata := []perfdataEntry{}
+
+	for _, state := range []string{"ALARM", "INSUFFICIENT_DATA", "OK"} {
+		perfdata = append(perfdata, perfdataEntry{
+			label:  strings.ToLower(state),
+			value:  float64(counts[state]),
+			levels: []string{"", "", "0", strconv.Itoa(len(results))},
+		})
+	}
+
+	return pluginOutput{
+		text:     msg,
+		perfdata: perfdata,
+	}
+}
+
+func (o summary) annotate(output pluginOutput, warnings []string) pluginOutput {
+	if len(warnings) == 0 {
+		return output
+	}
+
+	output.text += "; warning: " + strings.Join(warnings, ", ")
+
+	return output
+}
+
+func (o summary) appendDatapoints(output pluginOutput, datapoints []alert.Datapoint) pluginOutput {
+	if !o.longOutput || len(datapoints) == 0 {
+		return output
+	}
+
+	output.longOutput = formatDatapoints(datapoints)
+
+	return output
+}
+
+func (o summary) appendSeriesDatapoints(output pluginOutput, results []seriesResult) pluginOutput {
+	if !o.longOutput {
+		return output
+	}
+
+	sections := []string{}
+
+	for _, r := range results {
+		if len(r.datapoints) == 0 {
+			continue
+		}
+
+		sections = append(sections, r.metricName+":\n"+formatDatapoints(r.datapoints))
+	}
+
+	if len(sections) == 0 {
+		return output
+	}
+
+	output.longOutput = strings.Join(sections, "\n")
+
+	return output
+}
+
+func formatDatapoints(datapoints []alert.Datapoint) string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "timestamp\tvalue\tstatus")
+
+	for _, d := range datapoints {
+		timestamp := "-"
+
+		if !d.Timestamp.IsZero() {
+			timestamp = d.Timestamp.Format(time.RFC3339)
+		}
+
+		value := "missing"
+
+		if !d.IsMissing {
+			value = fmt.Sprintf("%g", d.Value)
+		}
+
+		status := alert.OK
+
+		if d.IsCritical {
+			status = alert.Critical
+		} else if d.IsWarn {
+			status = alert.Warning
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", timestamp, value, status)
+	}
+
+	w.Flush()
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func newPerfdata(name string, unit string, value float64, warnRange string, criticalRange string) perfdataEntry {
+	var min, max string
+
+	if unit == "Percent" {
+		min, max = "0", "100"
+	}
+
+	return perfdataEntry{
+		label:  name,
+		value:  value,
+		uom:    unitsOfMeasure[unit],
+		levels: []string{warnRange, criticalRange, min, max},
+	}
+}
+
+func (p perfdataEntry) String() string {
+	return fmt.Sprintf("%s=%g%s;%s", perfdataLabel(p.label), p.value, p.uom, strings.Join(p.levels, ";"))
+}
+
+func perfdataLabel(name string) string {
+	name = sanitizeLabel(name)
+
+	if strings.ContainsAny(name, " '") {
+		return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+	}
+
+	return name
+}
+
+func sanitizeLabel(name string) string {
+	return strings.NewReplacer("=", "_", "|", "_", "\n", " ").Replace(name)
+}
+
+func sanitizeText(text string) string {
+	return strings.NewReplacer("|", "/", "\n", " ").Replace(text)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -51,8 +52,9 @@ func Test_summary_print(t *testing.T) {
 	assert := assert.New(t)
 
 	type args struct {
-		returnCode alert.ReturnCode
-		msg        string
+		serviceName string
+		returnCode  alert.ReturnCode
+		msg         string
 	}
 
 	type testCase struct {
@@ -70,6 +72,15 @@ func Test_summary_print(t *testing.T) {
 			},
 			expected: "CLOUDWATCH OK: ok",
 		},
+		{
+			name: "service name",
+			args: args{
+				serviceName: "RDS-PROD",
+				returnCode:  alert.Critical,
+				msg:         "crit",
+			},
+			expected: "RDS-PROD CRITICAL: crit",
+		},
 		{
 			name: "warning",
 			args: args{
@@ -98,19 +109,24 @@ func Test_summary_print(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			s := newSummary(true, 0)
+			s := newSummary(formatClassic, 0)
+
+			if tc.args.serviceName != "" {
+				s.serviceName = tc.args.serviceName
+			}
 
 			ci := make(chan bool)
 
 			co := captureStdout(t, ci)
 
-			s.print(tc.args.returnCode, tc.args.msg)
+			returnCode := s.print(tc.args.returnCode, tc.args.msg)
 
 			ci <- true
 
 			output := <-co
 
 			assert.Equal(tc.expected, output, "output message")
+			assert.Equal(tc.args.returnCode, returnCode, "alert.ReturnCode")
 		})
 	}
 }
@@ -123,6 +139,7 @@ func Test_summary_build(t *testing.T) {
 		criticalRange       string
 		datapointsThreshold string
 		metricName          string
+		unit                string
 		value               float64
 		timestamp           time.Time
 		isWarn              bool
@@ -145,6 +162,7 @@ func Test_summary_build(t *testing.T) {
 				criticalRange:       "0:2",
 				datapointsThreshold: "3/4",
 				metricName:          "m1",
+				unit:                "",
 				value:               0.1,
 				timestamp:           time.Date(2022, time.January, 2, 3, 4, 5, 0, time.UTC),
 				isWarn:              false,
@@ -153,8 +171,8 @@ func Test_summary_build(t *testing.T) {
 				outOfCriticalRange:  3,
 			},
 			expected: []string{
-				"m1 = 0.1 | value=0.1;0:1;0:2;;",
-				"m1 = 0.1 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 2,3; threshold = 3/4 | value=0.1;0:1;0:2;; datapoints_warn=2;3/4;;; datapoints_crit=3;;3/4;;",
+				"m1 = 0.1 | m1=0.1;0:1;0:2;;",
+				"m1 = 0.1 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 2,3; threshold = 3/4 | m1=0.1;0:1;0:2;; datapoints_warn=2;3/4;;; datapoints_crit=3;;3/4;;",
 			},
 		},
 		{
@@ -164,6 +182,7 @@ func Test_summary_build(t *testing.T) {
 				criticalRange:       "",
 				datapointsThreshold: "5/6",
 				metricName:          "m2",
+				unit:                "Percent",
 				value:               2.1,
 				timestamp:           time.Date(2022, time.February, 3, 4, 5, 6, 0, time.UTC),
 				isWarn:              true,
@@ -172,8 +191,8 @@ func Test_summary_build(t *testing.T) {
 				outOfCriticalRange:  1,
 			},
 			expected: []string{
-				"m2 = 2.1; above thresholds = 5 | value=2.1;1:2;;; datapoints_warn=5;5/6;;;",
-				"m2 = 2.1 @ 2022-02-03 04:05:06 +0000 UTC; above thresholds [warn,crit] = 5,1; threshold = 5/6 | value=2.1;1:2;;; datapoints_warn=5;5/6;;; datapoints_crit=1;;5/6;;",
+				"m2 = 2.1; above thresholds = 5 | m2=2.1%;1:2;;0;100 datapoints_warn=5;5/6;;;",
+				"m2 = 2.1 @ 2022-02-03 04:05:06 +0000 UTC; above thresholds [warn,crit] = 5,1; threshold = 5/6 | m2=2.1%;1:2;;0;100 datapoints_warn=5;5/6;;; datapoints_crit=1;;5/6;;",
 			},
 		},
 		{
@@ -183,6 +202,7 @@ func Test_summary_build(t *testing.T) {
 				criticalRange:       "3:4",
 				datapointsThreshold: "6/7",
 				metricName:          "m3",
+				unit:                "Seconds",
 				value:               2.9,
 				timestamp:           time.Date(2022, time.March, 4, 5, 6, 7, 0, time.UTC),
 				isWarn:              false,
@@ -191,8 +211,8 @@ func Test_summary_build(t *testing.T) {
 				outOfCriticalRange:  6,
 			},
 			expected: []string{
-				"m3 = 2.9; above thresholds = 6 | value=2.9;;3:4;; datapoints_crit=6;;6/7;;",
-				"m3 = 2.9 @ 2022-03-04 05:06:07 +0000 UTC; above thresholds [warn,crit] = 1,6; threshold = 6/7 | value=2.9;;3:4;; datapoints_warn=1;6/7;;; datapoints_crit=6;;6/7;;",
+				"m3 = 2.9; above thresholds = 6 | m3=2.9s;;3:4;; datapoints_crit=6;;6/7;;",
+				"m3 = 2.9 @ 2022-03-04 05:06:07 +0000 UTC; above thresholds [warn,crit] = 1,6; threshold = 6/7 | m3=2.9s;;3:4;; datapoints_warn=1;6/7;;; datapoints_crit=6;;6/7;;",
 			},
 		},
 	}
@@ -201,37 +221,624 @@ func Test_summary_build(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			assert.Equal(
 				tc.expected[0],
-				newSummary(true, 0).build(
+				newSummary(formatClassic, 0).build(
 					tc.args.warnRange,
 					tc.args.criticalRange,
 					tc.args.datapointsThreshold,
 					tc.args.metricName,
+					tc.args.unit,
 					tc.args.value,
 					tc.args.timestamp,
 					tc.args.isWarn,
 					tc.args.isCritical,
 					tc.args.outOfWarnRange,
 					tc.args.outOfCriticalRange,
-				),
+				).String(),
 				"verbosity = 0",
 			)
 
 			assert.Equal(
 				tc.expected[1],
-				newSummary(true, 1).build(
+				newSummary(formatClassic, 1).build(
 					tc.args.warnRange,
 					tc.args.criticalRange,
 					tc.args.datapointsThreshold,
 					tc.args.metricName,
+					tc.args.unit,
 					tc.args.value,
 					tc.args.timestamp,
 					tc.args.isWarn,
 					tc.args.isCritical,
 					tc.args.outOfWarnRange,
 					tc.args.outOfCriticalRange,
-				),
+				).String(),
+				"verbosity = 1",
+			)
+		})
+	}
+}
+
+func Test_newPerfdata(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		name          string
+		unit          string
+		value         float64
+		warnRange     string
+		criticalRange string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "no unit",
+			args: args{
+				name:          "m1",
+				unit:          "None",
+				value:         1.5,
+				warnRange:     "0:1",
+				criticalRange: "0:2",
+			},
+			expected: "m1=1.5;0:1;0:2;;",
+		},
+		{
+			name: "percent",
+			args: args{
+				name:          "CPUUtilization",
+				unit:          "Percent",
+				value:         12.5,
+				warnRange:     "80",
+				criticalRange: "90",
+			},
+			expected: "CPUUtilization=12.5%;80;90;0;100",
+		},
+		{
+			name: "seconds",
+			args: args{
+				name:  "Latency",
+				unit:  "Seconds",
+				value: 0.25,
+			},
+			expected: "Latency=0.25s;;;;",
+		},
+		{
+			name: "bytes",
+			args: args{
+				name:  "NetworkIn",
+				unit:  "Bytes",
+				value: 1024,
+			},
+			expected: "NetworkIn=1024B;;;;",
+		},
+		{
+			name: "count",
+			args: args{
+				name:  "Errors",
+				unit:  "Count",
+				value: 3,
+			},
+			expected: "Errors=3c;;;;",
+		},
+		{
+			name: "sanitized label",
+			args: args{
+				name:  "it's a=b|c",
+				unit:  "",
+				value: 1,
+			},
+			expected: "'it''s a_b_c'=1;;;;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(
+				tc.expected,
+				newPerfdata(tc.args.name, tc.args.unit, tc.args.value, tc.args.warnRange, tc.args.criticalRange).String(),
+				"perfdata",
+			)
+		})
+	}
+}
+
+func Test_summary_buildSeries(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		results []seriesResult
+		listAll bool
+		top     int
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected []string
+	}
+
+	timestamp := time.Date(2022, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "ok",
+			args: args{
+				results: []seriesResult{
+					{
+						metricName:          "i-1",
+						value:               0.1,
+						timestamp:           timestamp,
+						returnCode:          alert.OK,
+						warnRange:           "0:1",
+						criticalRange:       "0:2",
+						datapointsThreshold: "1/2",
+						outOfWarnRange:      1,
+						outOfCriticalRange:  0,
+					},
+					{
+						metricName:          "i 2",
+						value:               0.2,
+						timestamp:           timestamp,
+						returnCode:          alert.OK,
+						warnRange:           "0:1",
+						criticalRange:       "0:2",
+						datapointsThreshold: "1/2",
+						outOfWarnRange:      0,
+						outOfCriticalRange:  0,
+					},
+				},
+				listAll: false,
+			},
+			expected: []string{
+				"2 series within thresholds | i-1=0.1;0:1;0:2;; 'i 2'=0.2;0:1;0:2;;",
+				"i-1 = 0.1 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 1,0; threshold = 1/2 (OK), i 2 = 0.2 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 0,0; threshold = 1/2 (OK) | i-1=0.1;0:1;0:2;; 'i 2'=0.2;0:1;0:2;;",
+			},
+		},
+		{
+			name: "unhealthy",
+			args: args{
+				results: []seriesResult{
+					{
+						metricName:          "i-1",
+						value:               2.5,
+						timestamp:           timestamp,
+						returnCode:          alert.Critical,
+						warnRange:           "0:1",
+						criticalRange:       "0:2",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      1,
+						outOfCriticalRange:  1,
+					},
+					{
+						metricName:          "i-2",
+						value:               0.5,
+						timestamp:           timestamp,
+						returnCode:          alert.OK,
+						warnRange:           "0:1",
+						criticalRange:       "0:2",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      0,
+						outOfCriticalRange:  0,
+					},
+					{
+						metricName:          "i-3",
+						returnCode:          alert.Unknown,
+						err:                 errors.New("no data"),
+						warnRange:           "0:1",
+						criticalRange:       "0:2",
+						datapointsThreshold: "1/1",
+					},
+				},
+				listAll: false,
+			},
+			expected: []string{
+				"2 of 3 series above thresholds: i-1 = 2.5 (CRITICAL), i-3 (UNKNOWN) | i-1=2.5;0:1;0:2;; i-2=0.5;0:1;0:2;;",
+				"i-1 = 2.5 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 1,1; threshold = 1/1 (CRITICAL), i-2 = 0.5 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 0,0; threshold = 1/1 (OK), i-3: no data (UNKNOWN) | i-1=2.5;0:1;0:2;; i-2=0.5;0:1;0:2;;",
+			},
+		},
+		{
+			name: "top offenders",
+			args: args{
+				results: []seriesResult{
+					{
+						metricName:          "fn-a",
+						value:               6,
+						timestamp:           timestamp,
+						returnCode:          alert.Warning,
+						warnRange:           "5",
+						criticalRange:       "10",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      1,
+						outOfCriticalRange:  0,
+					},
+					{
+						metricName:          "fn-b",
+						value:               12,
+						timestamp:           timestamp,
+						returnCode:          alert.Critical,
+						warnRange:           "5",
+						criticalRange:       "10",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      1,
+						outOfCriticalRange:  1,
+					},
+					{
+						metricName:          "fn-c",
+						value:               8,
+						timestamp:           timestamp,
+						returnCode:          alert.Warning,
+						warnRange:           "5",
+						criticalRange:       "10",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      1,
+						outOfCriticalRange:  0,
+					},
+					{
+						metricName:          "fn-d",
+						value:               1,
+						timestamp:           timestamp,
+						returnCode:          alert.OK,
+						warnRange:           "5",
+						criticalRange:       "10",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      0,
+						outOfCriticalRange:  0,
+					},
+				},
+				listAll: false,
+				top:     2,
+			},
+			expected: []string{
+				"3 of 4 series above thresholds: fn-b = 12 (CRITICAL), fn-c = 8 (WARNING), and 1 more | fn-a=6;5;10;; fn-b=12;5;10;; fn-c=8;5;10;; fn-d=1;5;10;;",
+				"fn-a = 6 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 1,0; threshold = 1/1 (WARNING), fn-b = 12 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 1,1; threshold = 1/1 (CRITICAL), fn-c = 8 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 1,0; threshold = 1/1 (WARNING), fn-d = 1 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 0,0; threshold = 1/1 (OK) | fn-a=6;5;10;; fn-b=12;5;10;; fn-c=8;5;10;; fn-d=1;5;10;;",
+			},
+		},
+		{
+			name: "list all",
+			args: args{
+				results: []seriesResult{
+					{
+						metricName:          "cpu",
+						value:               95,
+						timestamp:           timestamp,
+						returnCode:          alert.Critical,
+						warnRange:           "80",
+						criticalRange:       "90",
+						datapointsThreshold: "1/1",
+						outOfWarnRange:      1,
+						outOfCriticalRange:  1,
+					},
+					{
+						metricName:          "memory",
+						value:               1024,
+						timestamp:           timestamp,
+						returnCode:          alert.OK,
+						warnRange:           "@512",
+						criticalRange:       "@256",
+						datapointsThreshold: "3/5",
+						outOfWarnRange:      0,
+						outOfCriticalRange:  0,
+					},
+				},
+				listAll: true,
+			},
+			expected: []string{
+				"cpu = 95 (CRITICAL), memory = 1024 (OK) | cpu=95;80;90;; memory=1024;@512;@256;;",
+				"cpu = 95 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 1,1; threshold = 1/1 (CRITICAL), memory = 1024 @ 2022-01-02 03:04:05 +0000 UTC; above thresholds [warn,crit] = 0,0; threshold = 3/5 (OK) | cpu=95;80;90;; memory=1024;@512;@256;;",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(
+				tc.expected[0],
+				newSummary(formatClassic, 0).buildSeries(tc.args.results, tc.args.listAll, tc.args.top).String(),
+				"verbosity = 0",
+			)
+
+			assert.Equal(
+				tc.expected[1],
+				newSummary(formatClassic, 1).buildSeries(tc.args.results, tc.args.listAll, tc.args.top).String(),
+				"verbosity = 1",
+			)
+		})
+	}
+}
+
+func Test_summary_buildAlarms(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     []alarmResult
+		expected []string
+	}
+
+	updatedAt := time.Date(2022, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "single",
+			args: []alarmResult{
+				{
+					name:        "web-cpu",
+					state:       "ALARM",
+					stateReason: "Threshold Crossed: 1 datapoint [95.0] was greater than the threshold (90.0).",
+					updatedAt:   updatedAt,
+					returnCode:  alert.Critical,
+				},
+			},
+			expected: []string{
+				"web-cpu is ALARM: Threshold Crossed: 1 datapoint [95.0] was greater than the threshold (90.0). | alarm=1;;;0;1 insufficient_data=0;;;0;1 ok=0;;;0;1",
+				"web-cpu is ALARM @ 2022-01-02 03:04:05 +0000 UTC: Threshold Crossed: 1 datapoint [95.0] was greater than the threshold (90.0). (CRITICAL) | alarm=1;;;0;1 insufficient_data=0;;;0;1 ok=0;;;0;1",
+			},
+		},
+		{
+			name: "ok",
+			args: []alarmResult{
+				{
+					name:        "web-cpu",
+					state:       "OK",
+					stateReason: "Threshold Crossed",
+					updatedAt:   updatedAt,
+					returnCode:  alert.OK,
+				},
+				{
+					name:        "web-health",
+					composite:   true,
+					state:       "OK",
+					stateReason: "web-cpu transitioned to OK",
+					updatedAt:   updatedAt,
+					returnCode:  alert.OK,
+				},
+			},
+			expected: []string{
+				"2 alarms in OK state | alarm=0;;;0;2 insufficient_data=0;;;0;2 ok=2;;;0;2",
+				"web-cpu is OK @ 2022-01-02 03:04:05 +0000 UTC: Threshold Crossed (OK), web-health (composite) is OK @ 2022-01-02 03:04:05 +0000 UTC: web-cpu transitioned to OK (OK) | alarm=0;;;0;2 insufficient_data=0;;;0;2 ok=2;;;0;2",
+			},
+		},
+		{
+			name: "unhealthy",
+			args: []alarmResult{
+				{
+					name:        "web-cpu",
+					state:       "OK",
+					stateReason: "Threshold Crossed",
+					updatedAt:   updatedAt,
+					returnCode:  alert.OK,
+				},
+				{
+					name:        "web-health",
+					composite:   true,
+					state:       "ALARM",
+					stateReason: "web-memory transitioned to ALARM",
+					updatedAt:   updatedAt,
+					returnCode:  alert.Critical,
+				},
+				{
+					name:        "web-memory",
+					state:       "INSUFFICIENT_DATA",
+					stateReason: "Insufficient Data |\nno datapoints",
+					updatedAt:   updatedAt,
+					returnCode:  alert.Unknown,
+				},
+			},
+			expected: []string{
+				"2 of 3 alarms not in OK state: web-health (composite) is ALARM: web-memory transitioned to ALARM, web-memory is INSUFFICIENT_DATA: Insufficient Data / no datapoints | alarm=1;;;0;3 insufficient_data=1;;;0;3 ok=1;;;0;3",
+				"web-cpu is OK @ 2022-01-02 03:04:05 +0000 UTC: Threshold Crossed (OK), web-health (composite) is ALARM @ 2022-01-02 03:04:05 +0000 UTC: web-memory transitioned to ALARM (CRITICAL), web-memory is INSUFFICIENT_DATA @ 2022-01-02 03:04:05 +0000 UTC: Insufficient Data / no datapoints (UNKNOWN) | alarm=1;;;0;3 insufficient_data=1;;;0;3 ok=1;;;0;3",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(
+				tc.expected[0],
+				newSummary(formatClassic, 0).buildAlarms(tc.args).String(),
+				"verbosity = 0",
+			)
+
+			assert.Equal(
+				tc.expected[1],
+				newSummary(formatClassic, 1).buildAlarms(tc.args).String(),
 				"verbosity = 1",
 			)
 		})
 	}
 }
+
+func Test_summary_annotate(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		output   pluginOutput
+		warnings []string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "no warnings",
+			args: args{
+				output:   pluginOutput{text: "m1 = 0.1", perfdata: []perfdataEntry{newPerfdata("m1", "", 0.1, "0:1", "0:2")}},
+				warnings: []string{},
+			},
+			expected: "m1 = 0.1 | m1=0.1;0:1;0:2;;",
+		},
+		{
+			name: "with perfdata",
+			args: args{
+				output:   pluginOutput{text: "m1 = 0.1", perfdata: []perfdataEntry{newPerfdata("m1", "", 0.1, "0:1", "0:2")}},
+				warnings: []string{"partial data returned for m1", "m1: ArithmeticError"},
+			},
+			expected: "m1 = 0.1; warning: partial data returned for m1, m1: ArithmeticError | m1=0.1;0:1;0:2;;",
+		},
+		{
+			name: "without perfdata",
+			args: args{
+				output:   pluginOutput{text: "m1 = 0.1"},
+				warnings: []string{"partial data returned for m1"},
+			},
+			expected: "m1 = 0.1; warning: partial data returned for m1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, newSummary(formatClassic, 0).annotate(tc.args.output, tc.args.warnings).String(), "message")
+		})
+	}
+}
+
+func Test_formatDatapoints(t *testing.T) {
+	assert := assert.New(t)
+
+	timestamp := time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC)
+
+	datapoints := []alert.Datapoint{
+		{Timestamp: timestamp, Value: 0.5},
+		{Timestamp: timestamp.Add(-5 * time.Minute), Value: 12.25, IsWarn: true},
+		{IsMissing: true, IsWarn: true, IsCritical: true},
+	}
+
+	expected := "" +
+		"timestamp             value    status\n" +
+		"2022-09-19T10:20:00Z  0.5      OK\n" +
+		"2022-09-19T10:15:00Z  12.25    WARNING\n" +
+		"-                     missing  CRITICAL"
+
+	assert.Equal(expected, formatDatapoints(datapoints), "table")
+}
+
+func Test_summary_appendDatapoints(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		longOutput bool
+		datapoints []alert.Datapoint
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	timestamp := time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "long output",
+			args: args{
+				longOutput: true,
+				datapoints: []alert.Datapoint{
+					{Timestamp: timestamp, Value: 3, IsWarn: true, IsCritical: true},
+				},
+			},
+			expected: "" +
+				"m1 = 3; above thresholds = 1 | m1=3;0:1;0:2;; datapoints_crit=1;;1/1;;\n" +
+				"timestamp             value  status\n" +
+				"2022-09-19T10:20:00Z  3      CRITICAL",
+		},
+		{
+			name: "disabled",
+			args: args{
+				longOutput: false,
+				datapoints: []alert.Datapoint{
+					{Timestamp: timestamp, Value: 3, IsWarn: true, IsCritical: true},
+				},
+			},
+			expected: "m1 = 3; above thresholds = 1 | m1=3;0:1;0:2;; datapoints_crit=1;;1/1;;",
+		},
+		{
+			name: "no datapoints",
+			args: args{
+				longOutput: true,
+				datapoints: []alert.Datapoint{},
+			},
+			expected: "m1 = 3; above thresholds = 1 | m1=3;0:1;0:2;; datapoints_crit=1;;1/1;;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSummary(formatClassic, 0)
+
+			s.longOutput = tc.args.longOutput
+
+			assert.Equal(
+				tc.expected,
+				s.appendDatapoints(
+					pluginOutput{
+						text: "m1 = 3; above thresholds = 1",
+						perfdata: []perfdataEntry{
+							newPerfdata("m1", "", 3, "0:1", "0:2"),
+							{label: "datapoints_crit", value: 1, levels: []string{"", "1/1", "", ""}},
+						},
+					},
+					tc.args.datapoints,
+				).String(),
+				"message",
+			)
+		})
+	}
+}
+
+func Test_summary_appendSeriesDatapoints(t *testing.T) {
+	assert := assert.New(t)
+
+	timestamp := time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC)
+
+	results := []seriesResult{
+		{
+			metricName: "i-1",
+			datapoints: []alert.Datapoint{
+				{Timestamp: timestamp, Value: 0.5},
+			},
+		},
+		{
+			metricName: "i-2",
+			err:        errors.New("no data"),
+		},
+		{
+			metricName: "i-3",
+			datapoints: []alert.Datapoint{
+				{Timestamp: timestamp, Value: 1.5, IsWarn: true},
+			},
+		},
+	}
+
+	s := newSummary(formatClassic, 0)
+
+	s.longOutput = true
+
+	expected := "" +
+		"2 series within thresholds | i-1=0.5;;;; i-3=1.5;;;;\n" +
+		"i-1:\n" +
+		"timestamp             value  status\n" +
+		"2022-09-19T10:20:00Z  0.5    OK\n" +
+		"i-3:\n" +
+		"timestamp             value  status\n" +
+		"2022-09-19T10:20:00Z  1.5    WARNING"
+
+	output := pluginOutput{
+		text: "2 series within thresholds",
+		perfdata: []perfdataEntry{
+			newPerfdata("i-1", "", 0.5, "", ""),
+			newPerfdata("i-3", "", 1.5, "", ""),
+		},
+	}
+
+	assert.Equal(expected, s.appendSeriesDatapoints(output, results).String(), "message")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type outputData struct {
+	ServiceName         string
+	MetricName          string
+	Unit                string
+	Value               float64
+	Timestamp           time.Time
+	Status              string
+	WarnRange           string
+	CriticalRange       string
+	DatapointsThreshold string
+	IsWarn              bool
+	IsCritical          bool
+	OutOfWarnRange      int
+	OutOfCriticalRange  int
+	Perfdata            string
+}
+
+const defaultOutputTemplate string = "" +
+	`{{.MetricName}} = {{printf "%g" .Value}}` +
+	`{{if .IsCritical}}` +
+	`; above thresholds = {{.OutOfCriticalRange}}` +
+	`{{else if .IsWarn}}` +
+	`; above thresholds = {{.OutOfWarnRange}}` +
+	`{{end}}`
+
+const verboseOutputTemplate string = "" +
+	`{{.MetricName}} = {{printf "%g" .Value}} @ {{.Timestamp}}; ` +
+	`above thresholds [warn,crit] = {{.OutOfWarnRange}},{{.OutOfCriticalRange}}; threshold = {{.DatapointsThreshold}}`
+
+func newOutputTemplate(verbosity int) *template.Template {
+	if 1 <= verbosity {
+		return template.Must(template.New("output").Parse(verboseOutputTemplate))
+	}
+
+	return template.Must(template.New("output").Parse(defaultOutputTemplate))
+}
+
+func parseOutputTemplate(spec string) (*template.Template, error) {
+	text := spec
+
+	if strings.HasPrefix(spec, "@") {
+		log.V(3).Trace().
+			Str("package", "main").
+			Str("path", spec[1:]).
+			Msg("reading output template from file")
+
+		b, err := os.ReadFile(spec[1:])
+
+		if err != nil {
+			return nil, errors.NewArgumentErrorWithError(err, "output-template", spec)
+		}
+
+		text = strings.TrimRight(string(b), "\r\n")
+	}
+
+	t, err := template.New("output").Parse(text)
+
+	if err != nil {
+		return nil, errors.NewArgumentErrorWithError(err, "output-template", spec)
+	}
+
+	if err := t.Execute(io.Discard, outputData{}); err != nil {
+		return nil, errors.NewArgumentErrorWithError(err, "output-template", spec)
+	}
+
+	return t, nil
+}
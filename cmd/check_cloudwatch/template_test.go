@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseOutputTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		output string
+		err    error
+	}
+
+	type testCase struct {
+		name     string
+		args     string
+		expected expected
+	}
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "output.tmpl")
+
+	if err := os.WriteFile(path, []byte("{{.Status}}: {{.MetricName}}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []testCase{
+		{
+			name: "inline",
+			args: "{{.MetricName}} is {{.Value}}",
+			expected: expected{
+				output: "m1 is 1.5",
+				err:    nil,
+			},
+		},
+		{
+			name: "service name",
+			args: "{{.ServiceName}} {{.MetricName}}",
+			expected: expected{
+				output: "CLOUDWATCH m1",
+				err:    nil,
+			},
+		},
+		{
+			name: "file",
+			args: "@" + path,
+			expected: expected{
+				output: "WARNING: m1",
+				err:    nil,
+			},
+		},
+		{
+			name: "missing file",
+			args: "@" + filepath.Join(dir, "missing.tmpl"),
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "syntax error",
+			args: "{{.MetricName",
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "unknown field",
+			args: "{{.Account}}",
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := parseOutputTemplate(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+
+				return
+			}
+
+			assert.Nil(err, "is not error")
+
+			s := newSummary(formatClassic, 0)
+
+			s.template = tmpl
+
+			assert.Equal(
+				tc.expected.output,
+				s.build("0:1", "0:2", "1/1", "m1", "", 1.5, time.Date(2022, time.January, 2, 3, 4, 5, 0, time.UTC), true, false, 1, 0).text,
+				"message",
+			)
+		})
+	}
+}
+
+func Test_summary_build_template(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl, err := parseOutputTemplate(
+		"{{.MetricName}} = {{printf \"%.1f\" .Value}} ({{.Status}}, {{.OutOfCriticalRange}}/{{.DatapointsThreshold}}) " +
+			"see https://runbooks.example.com/cpu?view=a|b",
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newSummary(formatClassic, 0)
+
+	s.template = tmpl
+
+	assert.Equal(
+		pluginOutput{
+			text: "CPUUtilization = 95.3 (CRITICAL, 3/3/5) see https://runbooks.example.com/cpu?view=a/b",
+			perfdata: []perfdataEntry{
+				{label: "CPUUtilization", value: 95.26, uom: "%", levels: []string{"80", "90", "0", "100"}},
+				{label: "datapoints_crit", value: 3, levels: []string{"", "3/5", "", ""}},
+			},
+		},
+		s.build("80", "90", "3/5", "CPUUtilization", "Percent", 95.26, time.Date(2022, time.January, 2, 3, 4, 5, 0, time.UTC), true, true, 3, 3),
+		"output",
+	)
+}
@@ -0,0 +1,12 @@
+package alert
+
+func AlarmState(state string) ReturnCode {
+	switch state {
+	case "OK":
+		return OK
+	case "ALARM":
+		return Critical
+	default:
+		return Unknown
+	}
+}
@@ -0,0 +1,46 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AlarmState(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     string
+		expected ReturnCode
+	}
+
+	testCases := []testCase{
+		{
+			name:     "ok",
+			args:     "OK",
+			expected: OK,
+		},
+		{
+			name:     "alarm",
+			args:     "ALARM",
+			expected: Critical,
+		},
+		{
+			name:     "insufficient data",
+			args:     "INSUFFICIENT_DATA",
+			expected: Unknown,
+		},
+		{
+			name:     "unexpected",
+			args:     "UNEXPECTED",
+			expected: Unknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, AlarmState(tc.args), "ReturnCode")
+		})
+	}
+}
@@ -2,40 +2,94 @@ package alert
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
 )
 
 type Checker struct {
-	threshold          threshold
-	isWarn             bool
-	isCritical         bool
-	outOfWarnRange     int
-	outOfCriticalRange int
+	threshold           threshold
+	warnRange           string
+	criticalRange       string
+	datapointsThreshold string
+	isWarn              bool
+	isCritical          bool
+	outOfWarnRange      int
+	outOfCriticalRange  int
+	datapoints          []Datapoint
 }
 
-func NewChecker(warnRange string, criticalRange string, datapointsThreshold string) (Checker, error) {
+type Datapoint struct {
+	Timestamp  time.Time
+	Value      float64
+	IsMissing  bool
+	IsWarn     bool
+	IsCritical bool
+}
+
+func NewChecker(warnRange string, criticalRange string, datapointsThreshold string, missingData string) (Checker, error) {
 	log.V(3).Trace().
 		Str("package", "alert").
 		Msg("parsing thresholds")
 
-	threshold, err := newThreshold(warnRange, criticalRange, datapointsThreshold)
+	threshold, err := newThreshold(warnRange, criticalRange, datapointsThreshold, missingData)
 
 	if err != nil {
 		return Checker{}, err
 	}
 
 	return Checker{
-		threshold:          threshold,
-		isWarn:             false,
-		isCritical:         false,
-		outOfWarnRange:     0,
-		outOfCriticalRange: 0,
+		threshold:           threshold,
+		warnRange:           warnRange,
+		criticalRange:       criticalRange,
+		datapointsThreshold: datapointsThreshold,
+		isWarn:              false,
+		isCritical:          false,
+		outOfWarnRange:      0,
+		outOfCriticalRange:  0,
+		datapoints:          []Datapoint{},
 	}, nil
 }
 
+func NewQueryChecker(spec string, datapointsThreshold string, missingData string) (string, Checker, error) {
+	log.V(3).Trace().
+		Str("package", "alert").
+		Str("threshold", spec).
+		Msg("parsing query threshold")
+
+	id, ranges, found := strings.Cut(spec, "=")
+
+	if !found || id == "" {
+		return "", Checker{}, errors.NewArgumentErrorWithMessage("query threshold is specified in the format 'Id=WARN,CRIT[,n/m]'", "threshold", spec)
+	}
+
+	fields := strings.Split(ranges, ",")
+
+	switch len(fields) {
+	case 2:
+		fields = append(fields, datapointsThreshold)
+	case 3:
+	default:
+		return "", Checker{}, errors.NewArgumentErrorWithMessage("query threshold is specified in the format 'Id=WARN,CRIT[,n/m]'", "threshold", spec)
+	}
+
+	checker, err := NewChecker(fields[0], fields[1], fields[2], missingData)
+
+	if err != nil {
+		return "", Checker{}, err
+	}
+
+	return id, checker, nil
+}
+
 func (c *Checker) CheckStatus(values []float64) (ReturnCode, error) {
+	return c.checkStatus(nil, values)
+}
+
+func (c *Checker) checkStatus(timestamps []time.Time, values []float64) (ReturnCode, error) {
 	log.V(3).Trace().
 		Str("package", "alert").
 		Msg("checking if metrics are above thresholds")
@@ -48,12 +102,107 @@ func (c *Checker) CheckStatus(values []float64) (ReturnCode, error) {
 		)
 	}
 
+	return c.evaluate(timestamps, values)
+}
+
+func (c *Checker) CheckTimeSeries(
+	timestamps []time.Time, values []float64,
+	end time.Time, period time.Duration, duration time.Duration,
+) (ReturnCode, error) {
+	if c.threshold.missingData == treatIgnore {
+		return c.checkStatus(timestamps, values)
+	}
+
+	log.V(3).Trace().
+		Str("package", "alert").
+		Str("missing_data", c.threshold.missingData.String()).
+		Time("end_time", end).
+		Dur("period", period).
+		Dur("duration", duration).
+		Msg("placing metrics on the period grid")
+
+	if period <= 0 {
+		return Unknown, errors.NewArgumentErrorWithMessage(
+			"period of the metric is unknown; specify Period in the query",
+			"missing-data",
+			c.threshold.missingData.String(),
+		)
+	}
+
+	if periods := int(duration / period); periods < c.threshold.evaluationPeriods {
+		return Unknown, errors.NewArgumentErrorWithMessage(
+			fmt.Sprintf("duration covers only %d periods of %s", periods, period),
+			"datapoints",
+			fmt.Sprintf("%d/%d", c.threshold.datapointsToAlarm, c.threshold.evaluationPeriods),
+		)
+	}
+
+	grid := make([]float64, c.threshold.evaluationPeriods)
+	gridTimestamps := make([]time.Time, c.threshold.evaluationPeriods)
+
+	for i := range grid {
+		grid[i] = math.NaN()
+	}
+
+	open := end.Add(-time.Duration(end.UnixNano() % int64(period)))
+
+	log.V(3).Trace().
+		Str("package", "alert").
+		Time("open_period_start", open).
+		Msg("skipping the incomplete period")
+
+	present := 0
+
+	for i, timestamp := range timestamps {
+		if !timestamp.Before(open) || len(values) <= i {
+			continue
+		}
+
+		if slot := int((open.Sub(timestamp) - 1) / period); slot < len(grid) && math.IsNaN(grid[slot]) {
+			grid[slot] = values[i]
+			gridTimestamps[slot] = timestamp
+
+			present++
+		}
+	}
+
+	if c.threshold.missingData == treatMissing && present == 0 {
+		return Unknown, errors.NewArgumentErrorWithMessage(
+			"insufficient number of metrics to evaluate: got 0 datapoints",
+			"datapoints",
+			fmt.Sprintf("%d/%d", c.threshold.datapointsToAlarm, c.threshold.evaluationPeriods),
+		)
+	}
+
+	return c.evaluate(gridTimestamps, grid)
+}
+
+func (c *Checker) evaluate(timestamps []time.Time, values []float64) (ReturnCode, error) {
 	warnCounter := newCounter(c.threshold.warn, c.threshold.datapointsToAlarm)
 	criticalCounter := newCounter(c.threshold.critical, c.threshold.datapointsToAlarm)
 
+	c.datapoints = make([]Datapoint, 0, c.threshold.evaluationPeriods)
+
 	for i := range c.threshold.evaluationPeriods {
-		warnCounter.examine(values[i])
-		criticalCounter.examine(values[i])
+		d := Datapoint{}
+
+		if i < len(timestamps) {
+			d.Timestamp = timestamps[i]
+		}
+
+		if math.IsNaN(values[i]) {
+			breaching := c.threshold.missingData == treatBreaching
+
+			d.IsMissing = true
+			d.IsWarn = warnCounter.examineMissing(breaching)
+			d.IsCritical = criticalCounter.examineMissing(breaching)
+		} else {
+			d.Value = values[i]
+			d.IsWarn = warnCounter.examine(values[i])
+			d.IsCritical = criticalCounter.examine(values[i])
+		}
+
+		c.datapoints = append(c.datapoints, d)
 	}
 
 	c.outOfWarnRange = warnCounter.count
@@ -98,6 +247,14 @@ func (c *Checker) CheckStatus(values []float64) (ReturnCode, error) {
 	return OK, nil
 }
 
+func (c Checker) Thresholds() (warnRange string, criticalRange string, datapointsThreshold string) {
+	return c.warnRange, c.criticalRange, c.datapointsThreshold
+}
+
+func (c Checker) Datapoints() []Datapoint {
+	return c.datapoints
+}
+
 func (c Checker) Result() (isWarn bool, isCritical bool, outOfWarnRange int, outOfCriticalRange int) {
 	isWarn = c.isWarn
 	isCritical = c.isCritical
@@ -2,6 +2,7 @@ package alert
 
 import (
 	"testing"
+	"time"
 
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
 	"github.com/stretchr/testify/assert"
@@ -121,7 +122,7 @@ func Test_Checker_CheckStatus(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			c, err := NewChecker(tc.args.warnRange, tc.args.criticalRange, tc.args.datapointsThreshold)
+			c, err := NewChecker(tc.args.warnRange, tc.args.criticalRange, tc.args.datapointsThreshold, "ignore")
 
 			if err != nil {
 				t.Error(err)
@@ -140,6 +141,223 @@ func Test_Checker_CheckStatus(t *testing.T) {
 	}
 }
 
+func Test_Checker_CheckTimeSeries(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		datapointsThreshold string
+		missingData         string
+		timestamps          []time.Time
+		values              []float64
+		period              time.Duration
+		duration            time.Duration
+	}
+
+	type expected struct {
+		returnCode ReturnCode
+		err        error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	end := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	gappedTimestamps := []time.Time{
+		time.Date(2022, time.September, 19, 10, 15, 0, 0, time.UTC),
+		time.Date(2022, time.September, 19, 10, 5, 0, 0, time.UTC),
+	}
+
+	completeTimestamps := []time.Time{
+		time.Date(2022, time.September, 19, 10, 15, 0, 0, time.UTC),
+		time.Date(2022, time.September, 19, 10, 10, 0, 0, time.UTC),
+		time.Date(2022, time.September, 19, 10, 5, 0, 0, time.UTC),
+	}
+
+	testCases := []testCase{
+		{
+			name: "ignore",
+			args: args{
+				datapointsThreshold: "2/2",
+				missingData:         "ignore",
+				timestamps:          gappedTimestamps,
+				values:              []float64{5.0, 5.0},
+				period:              5 * time.Minute,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: Critical,
+				err:        nil,
+			},
+		},
+		{
+			name: "missing",
+			args: args{
+				datapointsThreshold: "3/3",
+				missingData:         "missing",
+				timestamps:          gappedTimestamps,
+				values:              []float64{5.0, 5.0},
+				period:              5 * time.Minute,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: OK,
+				err:        nil,
+			},
+		},
+		{
+			name: "missing without datapoints",
+			args: args{
+				datapointsThreshold: "1/2",
+				missingData:         "missing",
+				timestamps:          gappedTimestamps[1:],
+				values:              []float64{5.0},
+				period:              5 * time.Minute,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: Unknown,
+				err:        &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "breaching",
+			args: args{
+				datapointsThreshold: "2/2",
+				missingData:         "breaching",
+				timestamps:          gappedTimestamps,
+				values:              []float64{5.0, 5.0},
+				period:              5 * time.Minute,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: Critical,
+				err:        nil,
+			},
+		},
+		{
+			name: "notBreaching",
+			args: args{
+				datapointsThreshold: "2/2",
+				missingData:         "notBreaching",
+				timestamps:          gappedTimestamps,
+				values:              []float64{5.0, 5.0},
+				period:              5 * time.Minute,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: OK,
+				err:        nil,
+			},
+		},
+		{
+			name: "breaching with complete periods",
+			args: args{
+				datapointsThreshold: "1/3",
+				missingData:         "breaching",
+				timestamps:          completeTimestamps,
+				values:              []float64{1.0, 1.0, 1.0},
+				period:              5 * time.Minute,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: OK,
+				err:        nil,
+			},
+		},
+		{
+			name: "datapoint in incomplete period",
+			args: args{
+				datapointsThreshold: "1/3",
+				missingData:         "notBreaching",
+				timestamps: append(
+					[]time.Time{time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC)},
+					completeTimestamps...,
+				),
+				values:   []float64{5.0, 1.0, 1.0, 1.0},
+				period:   5 * time.Minute,
+				duration: 30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: OK,
+				err:        nil,
+			},
+		},
+		{
+			name: "unaligned timestamps",
+			args: args{
+				datapointsThreshold: "3/3",
+				missingData:         "breaching",
+				timestamps: []time.Time{
+					time.Date(2022, time.September, 19, 10, 19, 59, 0, time.UTC),
+					time.Date(2022, time.September, 19, 10, 14, 59, 0, time.UTC),
+					time.Date(2022, time.September, 19, 10, 9, 59, 0, time.UTC),
+				},
+				values:   []float64{5.0, 5.0, 5.0},
+				period:   5 * time.Minute,
+				duration: 30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: Critical,
+				err:        nil,
+			},
+		},
+		{
+			name: "unknown period",
+			args: args{
+				datapointsThreshold: "2/2",
+				missingData:         "breaching",
+				timestamps:          gappedTimestamps,
+				values:              []float64{5.0, 5.0},
+				period:              0,
+				duration:            30 * time.Minute,
+			},
+			expected: expected{
+				returnCode: Unknown,
+				err:        &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "duration too short",
+			args: args{
+				datapointsThreshold: "2/3",
+				missingData:         "breaching",
+				timestamps:          gappedTimestamps,
+				values:              []float64{5.0, 5.0},
+				period:              5 * time.Minute,
+				duration:            10 * time.Minute,
+			},
+			expected: expected{
+				returnCode: Unknown,
+				err:        &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewChecker("0.0:2.0", "0.0:4.0", tc.args.datapointsThreshold, tc.args.missingData)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			r, err := c.CheckTimeSeries(tc.args.timestamps, tc.args.values, end, tc.args.period, tc.args.duration)
+
+			assert.Equal(tc.expected.returnCode, r, "ReturnCode")
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+			}
+		})
+	}
+}
+
 func Test_Checker_Result(t *testing.T) {
 	assert := assert.New(t)
 
@@ -281,7 +499,7 @@ func Test_Checker_Result(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			c, err := NewChecker(tc.args.warnRange, tc.args.criticalRange, tc.args.datapointsThreshold)
+			c, err := NewChecker(tc.args.warnRange, tc.args.criticalRange, tc.args.datapointsThreshold, "ignore")
 
 			if err != nil {
 				t.Error(err)
@@ -298,3 +516,143 @@ func Test_Checker_Result(t *testing.T) {
 		})
 	}
 }
+
+func Test_Checker_Datapoints(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		missingData string
+		timestamps  []time.Time
+		values      []float64
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected []Datapoint
+	}
+
+	end := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	t1 := time.Date(2022, time.September, 19, 10, 15, 0, 0, time.UTC)
+	t2 := time.Date(2022, time.September, 19, 10, 10, 0, 0, time.UTC)
+	t3 := time.Date(2022, time.September, 19, 10, 5, 0, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "ignore",
+			args: args{
+				missingData: "ignore",
+				timestamps:  []time.Time{t1, t2, t3},
+				values:      []float64{0.5, 1.5, 2.5},
+			},
+			expected: []Datapoint{
+				{Timestamp: t1, Value: 0.5},
+				{Timestamp: t2, Value: 1.5, IsWarn: true},
+				{Timestamp: t3, Value: 2.5, IsWarn: true, IsCritical: true},
+			},
+		},
+		{
+			name: "breaching",
+			args: args{
+				missingData: "breaching",
+				timestamps:  []time.Time{t1, t3},
+				values:      []float64{0.5, 2.5},
+			},
+			expected: []Datapoint{
+				{Timestamp: t1, Value: 0.5},
+				{IsMissing: true, IsWarn: true, IsCritical: true},
+				{Timestamp: t3, Value: 2.5, IsWarn: true, IsCritical: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewChecker("0:1", "0:2", "3/3", tc.args.missingData)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			c.CheckTimeSeries(tc.args.timestamps, tc.args.values, end, 5*time.Minute, 30*time.Minute)
+
+			assert.Equal(tc.expected, c.Datapoints(), "datapoints")
+		})
+	}
+}
+
+func Test_NewQueryChecker(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		id         string
+		thresholds []string
+		err        error
+	}
+
+	type testCase struct {
+		name     string
+		args     string
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "with datapoints",
+			args: "cpu=80,90,3/5",
+			expected: expected{
+				id:         "cpu",
+				thresholds: []string{"80", "90", "3/5"},
+				err:        nil,
+			},
+		},
+		{
+			name: "without datapoints",
+			args: "memory=@512,",
+			expected: expected{
+				id:         "memory",
+				thresholds: []string{"@512", "", "1/1"},
+				err:        nil,
+			},
+		},
+		{
+			name: "missing Id",
+			args: "=80,90",
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "missing ranges",
+			args: "cpu=80",
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "invalid range",
+			args: "cpu=a,90",
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, c, err := NewQueryChecker(tc.args, "1/1", "ignore")
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				warnRange, criticalRange, datapointsThreshold := c.Thresholds()
+
+				assert.Equal(tc.expected.id, id, "id")
+				assert.Equal(tc.expected.thresholds, []string{warnRange, criticalRange, datapointsThreshold}, "thresholds")
+			}
+		})
+	}
+}
@@ -18,9 +18,9 @@ func newCounter(t thresholdRange, n int) counter {
 	}
 }
 
-func (c *counter) examine(value float64) {
+func (c *counter) examine(value float64) bool {
 	if !c.thresholdRange.enable {
-		return
+		return false
 	}
 
 	if c.outOfRange(value) {
@@ -34,6 +34,8 @@ func (c *counter) examine(value float64) {
 			Msg("the value is above threshold")
 
 		c.increment()
+
+		return true
 	} else {
 		log.V(3).Trace().
 			Str("package", "alert").
@@ -43,7 +45,26 @@ func (c *counter) examine(value float64) {
 			Float64("range_end", c.thresholdRange.end).
 			Bool("alert_if_inside_range", c.thresholdRange.inverse).
 			Msg("the value is below threshold")
+
+		return false
+	}
+}
+
+func (c *counter) examineMissing(breaching bool) bool {
+	if !c.thresholdRange.enable {
+		return false
 	}
+
+	log.V(3).Trace().
+		Str("package", "alert").
+		Bool("above_threshold", breaching).
+		Msg("the value is missing")
+
+	if breaching {
+		c.increment()
+	}
+
+	return breaching
 }
 
 func (c counter) outOfRange(value float64) bool {
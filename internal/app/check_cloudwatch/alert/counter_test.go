@@ -81,10 +81,73 @@ func Test_counter_examine(t *testing.T) {
 
 			c := newCounter(tr, 0)
 
+			breached := 0
+
 			for _, v := range tc.args.values {
-				c.examine(v)
+				if c.examine(v) {
+					breached++
+				}
 			}
 
+			assert.Equal(tc.expected, c.count, "count")
+			assert.Equal(tc.expected, breached, "breached")
+		})
+	}
+}
+
+func Test_counter_examineMissing(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		thresholdRange string
+		breaching      bool
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected int
+	}
+
+	testCases := []testCase{
+		{
+			name: "breaching",
+			args: args{
+				thresholdRange: "0.0:1.0",
+				breaching:      true,
+			},
+			expected: 1,
+		},
+		{
+			name: "not breaching",
+			args: args{
+				thresholdRange: "0.0:1.0",
+				breaching:      false,
+			},
+			expected: 0,
+		},
+		{
+			name: "not specified",
+			args: args{
+				thresholdRange: "",
+				breaching:      true,
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, err := newThresholdRange(tc.args.thresholdRange)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			c := newCounter(tr, 1)
+
+			c.examineMissing(tc.args.breaching)
+
 			assert.Equal(tc.expected, c.count, "count")
 		})
 	}
@@ -0,0 +1,45 @@
+package alert
+
+import (
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type missingDataTreatment int
+
+const (
+	treatIgnore missingDataTreatment = iota
+	treatMissing
+	treatBreaching
+	treatNotBreaching
+)
+
+func (m missingDataTreatment) String() string {
+	switch m {
+	case treatIgnore:
+		return "ignore"
+	case treatMissing:
+		return "missing"
+	case treatBreaching:
+		return "breaching"
+	case treatNotBreaching:
+		return "notBreaching"
+	default:
+		return "-"
+	}
+}
+
+func parseMissingDataTreatment(s string) (missingDataTreatment, error) {
+	for _, m := range []missingDataTreatment{treatIgnore, treatMissing, treatBreaching, treatNotBreaching} {
+		if s == m.String() {
+			log.V(3).Trace().
+				Str("package", "alert").
+				Str("missing_data", m.String()).
+				Send()
+
+			return m, nil
+		}
+	}
+
+	return treatIgnore, errors.NewArgumentErrorWithMessage("missing data treatment must be one of 'missing', 'ignore', 'breaching' or 'notBreaching'", "missing-data", s)
+}
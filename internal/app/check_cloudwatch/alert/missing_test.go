@@ -0,0 +1,80 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseMissingDataTreatment(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		treatment missingDataTreatment
+		err       error
+	}
+
+	type testCase struct {
+		name     string
+		args     string
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "ignore",
+			args: "ignore",
+			expected: expected{
+				treatment: treatIgnore,
+				err:       nil,
+			},
+		},
+		{
+			name: "missing",
+			args: "missing",
+			expected: expected{
+				treatment: treatMissing,
+				err:       nil,
+			},
+		},
+		{
+			name: "breaching",
+			args: "breaching",
+			expected: expected{
+				treatment: treatBreaching,
+				err:       nil,
+			},
+		},
+		{
+			name: "notBreaching",
+			args: "notBreaching",
+			expected: expected{
+				treatment: treatNotBreaching,
+				err:       nil,
+			},
+		},
+		{
+			name: "unknown",
+			args: "notbreaching",
+			expected: expected{
+				treatment: treatIgnore,
+				err:       &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			treatment, err := parseMissingDataTreatment(tc.args)
+
+			assert.Equal(tc.expected.treatment, treatment, "treatment")
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+			}
+		})
+	}
+}
@@ -23,3 +23,30 @@ func (r ReturnCode) String() string {
 		return "-"
 	}
 }
+
+func Worst(returnCodes ...ReturnCode) ReturnCode {
+	worst := OK
+
+	for _, r := range returnCodes {
+		if severity(worst) < severity(r) {
+			worst = r
+		}
+	}
+
+	return worst
+}
+
+func severity(r ReturnCode) int {
+	switch r {
+	case OK:
+		return 0
+	case Unknown:
+		return 1
+	case Warning:
+		return 2
+	case Critical:
+		return 3
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,51 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Worst(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     []ReturnCode
+		expected ReturnCode
+	}
+
+	testCases := []testCase{
+		{
+			name:     "empty",
+			args:     []ReturnCode{},
+			expected: OK,
+		},
+		{
+			name:     "ok",
+			args:     []ReturnCode{OK, OK},
+			expected: OK,
+		},
+		{
+			name:     "unknown",
+			args:     []ReturnCode{OK, Unknown},
+			expected: Unknown,
+		},
+		{
+			name:     "warning",
+			args:     []ReturnCode{Unknown, Warning, OK},
+			expected: Warning,
+		},
+		{
+			name:     "critical",
+			args:     []ReturnCode{Warning, Critical, Unknown},
+			expected: Critical,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, Worst(tc.args...), "ReturnCode")
+		})
+	}
+}
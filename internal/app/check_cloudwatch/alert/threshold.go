@@ -15,6 +15,7 @@ type threshold struct {
 	datapointsToAlarm int
 	warn              thresholdRange
 	critical          thresholdRange
+	missingData       missingDataTreatment
 }
 
 type thresholdRange struct {
@@ -24,7 +25,7 @@ type thresholdRange struct {
 	inverse bool
 }
 
-func newThreshold(warnRange string, criticalRange string, datapointsThreshold string) (threshold, error) {
+func newThreshold(warnRange string, criticalRange string, datapointsThreshold string, missingData string) (threshold, error) {
 	log.V(3).Trace().
 		Str("package", "alert").
 		Msg("parsing warn threshold")
@@ -55,11 +56,22 @@ func newThreshold(warnRange string, criticalRange string, datapointsThreshold st
 		return threshold{}, err
 	}
 
+	log.V(3).Trace().
+		Str("package", "alert").
+		Msg("parsing missing data treatment")
+
+	treatment, err := parseMissingDataTreatment(missingData)
+
+	if err != nil {
+		return threshold{}, err
+	}
+
 	return threshold{
 		evaluationPeriods: evaluationPeriods,
 		datapointsToAlarm: datapointsToAlarm,
 		warn:              warn,
 		critical:          critical,
+		missingData:       treatment,
 	}, nil
 }
 
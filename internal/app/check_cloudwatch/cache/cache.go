@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type Cache struct {
+	dir  string
+	ttl  time.Duration
+	wait time.Duration
+}
+
+type entry[T any] struct {
+	Time  time.Time `json:"time"`
+	Value T         `json:"value"`
+}
+
+const lockPollInterval time.Duration = 50 * time.Millisecond
+
+var errLocked = errors.New("cache is locked by another process")
+
+func New(dir string, ttl int, wait time.Duration) *Cache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	if dir == "" {
+		base, err := os.UserCacheDir()
+
+		if err != nil {
+			log.V(2).Debug().
+				Str("package", "cache").
+				Err(err).
+				Msg("cache is not available")
+
+			return nil
+		}
+
+		dir = filepath.Join(base, "check_cloudwatch")
+	}
+
+	return &Cache{
+		dir:  dir,
+		ttl:  time.Duration(ttl) * time.Second,
+		wait: wait,
+	}
+}
+
+func Key(v any) string {
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func Fetch[T any](c *Cache, key string, now time.Time, fetch func() (T, error)) (T, error) {
+	if c == nil || key == "" {
+		return fetch()
+	}
+
+	unlock, err := c.lock(key)
+
+	if err != nil {
+		log.V(2).Debug().
+			Str("package", "cache").
+			Err(err).
+			Str("cache_dir", c.dir).
+			Msg("cache is not available")
+
+		return fetch()
+	}
+
+	defer unlock()
+
+	if v, ok := read[T](c.path(key), now, c.ttl); ok {
+		log.V(3).Trace().
+			Str("package", "cache").
+			Str("key", key).
+			Msg("serving from cache")
+
+		return v, nil
+	}
+
+	v, err := fetch()
+
+	if err != nil {
+		return v, err
+	}
+
+	if err := write(c.path(key), entry[T]{Time: now, Value: v}); err != nil {
+		log.V(2).Debug().
+			Str("package", "cache").
+			Err(err).
+			Str("key", key).
+			Msg("failed to write cache")
+	}
+
+	return v, nil
+}
+
+func (c Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c Cache) lock(key string) (func(), error) {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	if err := checkDir(c.dir); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(c.dir, key+".lock"), os.O_RDWR|os.O_CREATE, 0o600)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.wait)
+
+	for {
+		err := tryLockFile(f)
+
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, errLocked) || !time.Now().Before(deadline) {
+			f.Close()
+
+			return nil, err
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+
+	log.V(3).Trace().
+		Str("package", "cache").
+		Str("key", key).
+		Msg("cache locked")
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+func read[T any](path string, now time.Time, ttl time.Duration) (T, bool) {
+	var e entry[T]
+
+	b, err := os.ReadFile(path)
+
+	if err != nil {
+		return e.Value, false
+	}
+
+	if err := json.Unmarshal(b, &e); err != nil {
+		return e.Value, false
+	}
+
+	if age := now.Sub(e.Time); age < 0 || ttl <= age {
+		log.V(3).Trace().
+			Str("package", "cache").
+			Str("path", path).
+			Time("cached_at", e.Time).
+			Msg("cache expired")
+
+		var zero T
+
+		return zero, false
+	}
+
+	return e.Value, true
+}
+
+func write(path string, v any) error {
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
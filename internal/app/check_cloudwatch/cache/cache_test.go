@@ -0,0 +1,251 @@
+package cache
+
+import (
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_New(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	assert.Nil(New("", 0, time.Second), "disabled")
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	base, err := os.UserCacheDir()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(&Cache{dir: filepath.Join(base, "check_cloudwatch"), ttl: time.Minute, wait: time.Second}, New("", 60, time.Second), "default directory")
+
+	assert.Equal(&Cache{dir: "/var/cache/check_cloudwatch", ttl: time.Minute, wait: time.Second}, New("/var/cache/check_cloudwatch", 60, time.Second), "directory")
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+
+	if _, err := os.UserCacheDir(); err != nil {
+		assert.Nil(New("", 60, time.Second), "no cache directory")
+	}
+}
+
+func Test_Key(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(Key([]string{"a", "b"}), Key([]string{"a", "b"}), "same value")
+	assert.NotEqual(Key([]string{"a", "b"}), Key([]string{"b", "a"}), "different value")
+	assert.Len(Key(1), 64, "length")
+}
+
+func Test_Fetch(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		ttl      int
+		cachedAt time.Duration
+		err      error
+	}
+
+	type expected struct {
+		value   int
+		fetched bool
+		err     bool
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "fresh",
+			args: args{
+				ttl:      60,
+				cachedAt: -59 * time.Second,
+			},
+			expected: expected{
+				value:   1,
+				fetched: false,
+			},
+		},
+		{
+			name: "expired",
+			args: args{
+				ttl:      60,
+				cachedAt: -60 * time.Second,
+			},
+			expected: expected{
+				value:   2,
+				fetched: true,
+			},
+		},
+		{
+			name: "from the future",
+			args: args{
+				ttl:      60,
+				cachedAt: time.Second,
+			},
+			expected: expected{
+				value:   2,
+				fetched: true,
+			},
+		},
+		{
+			name: "disabled",
+			args: args{
+				ttl:      0,
+				cachedAt: 0,
+			},
+			expected: expected{
+				value:   2,
+				fetched: true,
+			},
+		},
+		{
+			name: "fetch error",
+			args: args{
+				ttl:      60,
+				cachedAt: -60 * time.Second,
+				err:      goerrors.New(""),
+			},
+			expected: expected{
+				value:   2,
+				fetched: true,
+				err:     true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			dir := t.TempDir()
+
+			if err := write(filepath.Join(dir, "key.json"), entry[int]{Time: now.Add(tc.args.cachedAt), Value: 1}); err != nil {
+				t.Fatal(err)
+			}
+
+			fetched := false
+
+			v, err := Fetch(New(dir, tc.args.ttl, time.Second), "key", now, func() (int, error) {
+				fetched = true
+
+				return 2, tc.args.err
+			})
+
+			assert.Equal(tc.expected.value, v, "value")
+			assert.Equal(tc.expected.fetched, fetched, "fetched")
+
+			if tc.expected.err {
+				assert.NotNil(err, "is error")
+
+				return
+			}
+
+			assert.Nil(err, "is not error")
+
+			cached, ok := read[int](filepath.Join(dir, "key.json"), now, time.Minute)
+
+			if tc.args.ttl == 0 {
+				assert.Equal(1, cached, "cached value is untouched")
+			} else {
+				assert.True(ok, "is cached")
+				assert.Equal(tc.expected.value, cached, "cached value")
+			}
+		})
+	}
+}
+
+func Test_Fetch_concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	log.SetVerbosity(0)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	c := New(t.TempDir(), 60, time.Second)
+
+	var mu sync.Mutex
+
+	calls := 0
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, err := Fetch(c, "key", now, func() (int, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				calls++
+
+				time.Sleep(10 * time.Millisecond)
+
+				return 1, nil
+			})
+
+			assert.Nil(err, "is not error")
+			assert.Equal(1, v, "value")
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(1, calls, "fetch calls")
+}
+
+func Test_Fetch_locked(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	dir := t.TempDir()
+
+	c := New(dir, 60, 100*time.Millisecond)
+
+	unlock, err := c.lock("key")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer unlock()
+
+	start := time.Now()
+
+	v, err := Fetch(c, "key", now, func() (int, error) {
+		return 1, nil
+	})
+
+	assert.Nil(err, "is not error")
+	assert.Equal(1, v, "value")
+	assert.GreaterOrEqual(time.Since(start), 100*time.Millisecond, "waited for the lock")
+
+	_, ok := read[int](filepath.Join(dir, "key.json"), now, time.Minute)
+
+	assert.False(ok, "is not cached")
+}
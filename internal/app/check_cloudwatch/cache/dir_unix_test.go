@@ -0,0 +1,107 @@
+//go:build unix
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkDir(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     func(dir string) string
+		expected bool
+	}
+
+	testCases := []testCase{
+		{
+			name: "private",
+			args: func(dir string) string {
+				return dir
+			},
+			expected: true,
+		},
+		{
+			name: "writable by others",
+			args: func(dir string) string {
+				if err := os.Chmod(dir, 0o777); err != nil {
+					t.Fatal(err)
+				}
+
+				return dir
+			},
+			expected: false,
+		},
+		{
+			name: "symbolic link",
+			args: func(dir string) string {
+				link := filepath.Join(t.TempDir(), "link")
+
+				if err := os.Symlink(dir, link); err != nil {
+					t.Fatal(err)
+				}
+
+				return link
+			},
+			expected: false,
+		},
+		{
+			name: "file",
+			args: func(dir string) string {
+				path := filepath.Join(dir, "file")
+
+				if err := os.WriteFile(path, []byte{}, 0o600); err != nil {
+					t.Fatal(err)
+				}
+
+				return path
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkDir(tc.args(t.TempDir()))
+
+			if tc.expected {
+				assert.Nil(err, "is not error")
+			} else {
+				assert.NotNil(err, "is error")
+			}
+		})
+	}
+}
+
+func Test_Fetch_unsafeDir(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	dir := t.TempDir()
+
+	if err := write(filepath.Join(dir, "key.json"), entry[int]{Time: now, Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := Fetch(New(dir, 60, time.Second), "key", now, func() (int, error) {
+		return 2, nil
+	})
+
+	assert.Nil(err, "is not error")
+	assert.Equal(2, v, "value is not served from the cache")
+}
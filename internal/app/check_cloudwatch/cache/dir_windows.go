@@ -0,0 +1,22 @@
+//go:build windows
+
+package cache
+
+import (
+	"fmt"
+	"os"
+)
+
+func checkDir(dir string) error {
+	info, err := os.Lstat(dir)
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package cloudwatch
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/container"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type Alarm struct {
+	Name        string
+	Composite   bool
+	State       string
+	StateReason string
+	UpdatedAt   time.Time
+}
+
+const alarmStateNotFound string = "NOT_FOUND"
+
+type Alarms struct {
+	client  types.Client
+	names   []string
+	prefix  string
+	timeout int
+}
+
+func NewAlarms(names []string, prefix string, options types.Options, timeout int) (Alarms, error) {
+	client, err := container.GetCloudWatchClient(options)
+
+	if err != nil {
+		return Alarms{}, err
+	}
+
+	return Alarms{
+		client:  client,
+		names:   names,
+		prefix:  prefix,
+		timeout: timeout,
+	}, nil
+}
+
+func (a Alarms) Describe(now time.Time) ([]Alarm, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Strs("alarm_names", a.names).
+		Str("alarm_prefix", a.prefix).
+		Int("timeout", a.timeout).
+		Msg("calling DescribeAlarms API")
+
+	ctx, cancel := context.WithDeadline(
+		context.Background(),
+		now.Add(time.Duration(a.timeout)*time.Second),
+	)
+
+	defer cancel()
+
+	input := &cloudwatch.DescribeAlarmsInput{
+		AlarmTypes: []awstypes.AlarmType{
+			awstypes.AlarmTypeMetricAlarm,
+			awstypes.AlarmTypeCompositeAlarm,
+		},
+	}
+
+	if len(a.names) != 0 {
+		input.AlarmNames = a.names
+	} else {
+		input.AlarmNamePrefix = aws.String(a.prefix)
+	}
+
+	result := &cloudwatch.DescribeAlarmsOutput{}
+
+	for page := 1; ; page++ {
+		output, err := a.client.DescribeAlarms(ctx, input)
+
+		if err != nil {
+			return []Alarm{}, errors.NewCloudWatchError(err)
+		}
+
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Int("page", page).
+			Bool("has_next_page", output.NextToken != nil).
+			Msg("API page retrieved")
+
+		result.MetricAlarms = append(result.MetricAlarms, output.MetricAlarms...)
+		result.CompositeAlarms = append(result.CompositeAlarms, output.CompositeAlarms...)
+
+		if output.NextToken == nil {
+			break
+		}
+
+		input = &cloudwatch.DescribeAlarmsInput{
+			AlarmNames:      input.AlarmNames,
+			AlarmNamePrefix: input.AlarmNamePrefix,
+			AlarmTypes:      input.AlarmTypes,
+			NextToken:       output.NextToken,
+		}
+	}
+
+	printAlarms(result)
+
+	alarms := make([]Alarm, 0, len(result.MetricAlarms)+len(result.CompositeAlarms))
+
+	for _, m := range result.MetricAlarms {
+		alarms = append(alarms, Alarm{
+			Name:        aws.ToString(m.AlarmName),
+			State:       string(m.StateValue),
+			StateReason: aws.ToString(m.StateReason),
+			UpdatedAt:   aws.ToTime(m.StateUpdatedTimestamp),
+		})
+	}
+
+	for _, c := range result.CompositeAlarms {
+		alarms = append(alarms, Alarm{
+			Name:        aws.ToString(c.AlarmName),
+			Composite:   true,
+			State:       string(c.StateValue),
+			StateReason: aws.ToString(c.StateReason),
+			UpdatedAt:   aws.ToTime(c.StateUpdatedTimestamp),
+		})
+	}
+
+	for _, name := range a.names {
+		if slices.ContainsFunc(alarms, func(x Alarm) bool { return x.Name == name }) {
+			continue
+		}
+
+		log.V(2).Debug().
+			Str("alarm_name", name).
+			Msg("alarm not found")
+
+		alarms = append(alarms, Alarm{
+			Name:        name,
+			State:       alarmStateNotFound,
+			StateReason: "alarm does not exist",
+		})
+	}
+
+	if len(alarms) == 0 {
+		return []Alarm{}, errors.NewCloudWatchError(goerrors.New("no alarms found"))
+	}
+
+	slices.SortStableFunc(alarms, func(x, y Alarm) int {
+		return strings.Compare(x.Name, y.Name)
+	})
+
+	return alarms, nil
+}
+
+func printAlarms(result *cloudwatch.DescribeAlarmsOutput) {
+	r, err := json.Marshal(result)
+
+	if err != nil {
+		log.V(2).Error().
+			Err(err).
+			Msg("failed to marshal DescribeAlarmsOutput")
+	} else {
+		log.V(2).Debug().
+			RawJSON("DescribeAlarmsOutput", r).
+			Msg("API call succeeds")
+	}
+}
@@ -0,0 +1,306 @@
+package cloudwatch
+
+import (
+	goerrors "errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func Test_NewAlarms(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		factory func(types.Options) (types.Client, error)
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			args: args{
+				factory: func(types.Options) (types.Client, error) {
+					return &mock.CloudWatchClient{}, nil
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "client error",
+			args: args{
+				factory: func(types.Options) (types.Client, error) {
+					return &mock.CloudWatchClient{}, errors.CloudWatchError{}
+				},
+			},
+			expected: &errors.CloudWatchError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetCloudWatchClientFactory(t, tc.args.factory)
+
+			a, err := NewAlarms([]string{"a"}, "", types.Options{}, 5)
+
+			if tc.expected != nil {
+				assert.ErrorAs(err, tc.expected, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal([]string{"a"}, a.names, "names")
+				assert.Equal(5, a.timeout, "timeout")
+			}
+		})
+	}
+}
+
+func Test_Alarms_Describe(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		names   []string
+		prefix  string
+		factory func(types.Options) (types.Client, error)
+	}
+
+	type expected struct {
+		alarms []Alarm
+		err    error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+	updatedAt := time.Date(2022, time.September, 19, 10, 15, 0, 0, time.UTC)
+
+	alarmTypes := []awstypes.AlarmType{
+		awstypes.AlarmTypeMetricAlarm,
+		awstypes.AlarmTypeCompositeAlarm,
+	}
+
+	testCases := []testCase{
+		{
+			name: "names",
+			args: args{
+				names: []string{"web-cpu", "web-health"},
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					input := &cloudwatch.DescribeAlarmsInput{
+						AlarmNames: []string{"web-cpu", "web-health"},
+						AlarmTypes: alarmTypes,
+					}
+
+					output := &cloudwatch.DescribeAlarmsOutput{
+						MetricAlarms: []awstypes.MetricAlarm{
+							{
+								AlarmName:             aws.String("web-cpu"),
+								StateValue:            awstypes.StateValueAlarm,
+								StateReason:           aws.String("Threshold Crossed"),
+								StateUpdatedTimestamp: aws.Time(updatedAt),
+							},
+						},
+						CompositeAlarms: []awstypes.CompositeAlarm{
+							{
+								AlarmName:             aws.String("web-health"),
+								StateValue:            awstypes.StateValueOk,
+								StateReason:           aws.String("arn:aws:cloudwatch:ap-northeast-1:123456789012:alarm:web-cpu transitioned to OK"),
+								StateUpdatedTimestamp: aws.Time(updatedAt),
+							},
+						},
+					}
+
+					m.On("DescribeAlarms", testifymock.Anything, input).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				alarms: []Alarm{
+					{
+						Name:        "web-cpu",
+						State:       "ALARM",
+						StateReason: "Threshold Crossed",
+						UpdatedAt:   updatedAt,
+					},
+					{
+						Name:        "web-health",
+						Composite:   true,
+						State:       "OK",
+						StateReason: "arn:aws:cloudwatch:ap-northeast-1:123456789012:alarm:web-cpu transitioned to OK",
+						UpdatedAt:   updatedAt,
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "prefix with pagination",
+			args: args{
+				prefix: "web-",
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					input1 := &cloudwatch.DescribeAlarmsInput{
+						AlarmNamePrefix: aws.String("web-"),
+						AlarmTypes:      alarmTypes,
+					}
+
+					output1 := &cloudwatch.DescribeAlarmsOutput{
+						MetricAlarms: []awstypes.MetricAlarm{
+							{
+								AlarmName:  aws.String("web-memory"),
+								StateValue: awstypes.StateValueInsufficientData,
+							},
+						},
+						NextToken: aws.String("token1"),
+					}
+
+					input2 := &cloudwatch.DescribeAlarmsInput{
+						AlarmNamePrefix: aws.String("web-"),
+						AlarmTypes:      alarmTypes,
+						NextToken:       aws.String("token1"),
+					}
+
+					output2 := &cloudwatch.DescribeAlarmsOutput{
+						MetricAlarms: []awstypes.MetricAlarm{
+							{
+								AlarmName:  aws.String("web-cpu"),
+								StateValue: awstypes.StateValueOk,
+							},
+						},
+					}
+
+					m.On("DescribeAlarms", testifymock.Anything, input1).Return(output1, nil)
+					m.On("DescribeAlarms", testifymock.Anything, input2).Return(output2, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				alarms: []Alarm{
+					{
+						Name:  "web-cpu",
+						State: "OK",
+					},
+					{
+						Name:  "web-memory",
+						State: "INSUFFICIENT_DATA",
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "missing names",
+			args: args{
+				names: []string{"web-cpu", "web-cpuu"},
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.DescribeAlarmsOutput{
+						MetricAlarms: []awstypes.MetricAlarm{
+							{
+								AlarmName:  aws.String("web-cpu"),
+								StateValue: awstypes.StateValueOk,
+							},
+						},
+					}
+
+					m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				alarms: []Alarm{
+					{
+						Name:  "web-cpu",
+						State: "OK",
+					},
+					{
+						Name:        "web-cpuu",
+						State:       "NOT_FOUND",
+						StateReason: "alarm does not exist",
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "not found",
+			args: args{
+				prefix: "UNKNOWN-",
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.DescribeAlarmsOutput{}, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				alarms: []Alarm{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "API error",
+			args: args{
+				names: []string{"web-cpu"},
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.DescribeAlarmsOutput{}, goerrors.New(""))
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				alarms: []Alarm{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+			helper.SetCloudWatchClientFactory(t, tc.args.factory)
+
+			a, err := NewAlarms(tc.args.names, tc.args.prefix, types.Options{}, 5)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			alarms, err := a.Describe(now)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+			}
+
+			assert.Equal(tc.expected.alarms, alarms, "alarms")
+		})
+	}
+}
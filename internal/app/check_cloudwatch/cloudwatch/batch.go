@@ -0,0 +1,259 @@
+package cloudwatch
+
+import (
+	goerrors "errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cache"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+const maxQueriesPerRequest int = 500
+
+const maxQueryIDLength int = 255
+
+var batchIDPattern = regexp.MustCompile(`^c(\d+)_(.+)$`)
+
+var queryIDPattern = regexp.MustCompile(`^[a-z][A-Za-z0-9_]*$`)
+
+type Batch struct {
+	duration int
+	checks   [][]awstypes.MetricDataQuery
+	timeout  int
+	options  types.Options
+}
+
+type BatchResult struct {
+	Series []Series
+	Err    error
+}
+
+func NewBatch(
+	duration int, checks [][]awstypes.MetricDataQuery,
+	options types.Options, timeout int,
+) Batch {
+	return Batch{
+		duration: duration,
+		checks:   checks,
+		timeout:  timeout,
+		options:  options,
+	}
+}
+
+func (b Batch) GetMetricSeries(now time.Time) []BatchResult {
+	results := make([]BatchResult, len(b.checks))
+
+	chunks := [][]int{}
+	sizes := []int{}
+
+	for i, queries := range b.checks {
+		switch {
+		case len(queries) == 0:
+			results[i].Err = errors.NewArgumentErrorWithMessage("queries must be an array of MetricDataQuery objects", "queries", "")
+
+			continue
+		case maxQueriesPerRequest < len(queries):
+			results[i].Err = errors.NewArgumentErrorWithMessage(
+				fmt.Sprintf("a check can have at most %d queries", maxQueriesPerRequest),
+				"queries",
+				strconv.Itoa(len(queries)),
+			)
+
+			continue
+		}
+
+		if err := validateQueryIDs(i, queries); err != nil {
+			results[i].Err = err
+
+			continue
+		}
+
+		placed := false
+
+		for j := range chunks {
+			if sizes[j]+len(queries) <= maxQueriesPerRequest {
+				chunks[j] = append(chunks[j], i)
+				sizes[j] += len(queries)
+				placed = true
+
+				break
+			}
+		}
+
+		if !placed {
+			chunks = append(chunks, []int{i})
+			sizes = append(sizes, len(queries))
+		}
+	}
+
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Int("checks", len(b.checks)).
+		Ints("queries_per_request", sizes).
+		Msg("packed checks into API requests")
+
+	var client types.Client
+
+	for _, chunk := range chunks {
+		queries := []awstypes.MetricDataQuery{}
+
+		for _, i := range chunk {
+			queries = append(queries, prefixQueries(i, b.checks[i])...)
+		}
+
+		c := CloudWatch{
+			client:   client,
+			options:  b.options,
+			duration: b.duration,
+			queries:  queries,
+			timeout:  b.timeout,
+			cache:    cache.New(b.options.CacheDir, b.options.CacheTTL, time.Duration(b.timeout)*time.Second/2),
+			cacheKey: metricDataCacheKey(b.duration, queries, b.options),
+		}
+
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Ints("checks", chunk).
+			Msg("calling GetMetricData API")
+
+		err := c.getMetricData(now)
+
+		client = c.client
+
+		if err != nil {
+			for _, i := range chunk {
+				results[i].Err = err
+			}
+
+			continue
+		}
+
+		c.printResult()
+
+		checkResults := map[int][]awstypes.MetricDataResult{}
+
+		for _, r := range c.result.MetricDataResults {
+			m := batchIDPattern.FindStringSubmatch(aws.ToString(r.Id))
+
+			if m == nil {
+				continue
+			}
+
+			i, _ := strconv.Atoi(m[1])
+
+			s := c.newSeries(r)
+
+			if s.Label == s.Id {
+				s.Label = m[2]
+			}
+
+			s.Id = m[2]
+
+			results[i].Series = append(results[i].Series, s)
+
+			r.Id = aws.String(m[2])
+
+			checkResults[i] = append(checkResults[i], r)
+		}
+
+		for _, i := range chunk {
+			if err := c.checkStatusCodes(checkResults[i]); err != nil {
+				results[i] = BatchResult{
+					Err: err,
+				}
+			} else if len(results[i].Series) == 0 {
+				results[i].Err = errors.NewCloudWatchError(goerrors.New("no metric data results returned"))
+			}
+		}
+	}
+
+	return results
+}
+
+func validateQueryIDs(index int, queries []awstypes.MetricDataQuery) error {
+	prefix := fmt.Sprintf("c%d_", index)
+
+	for _, q := range queries {
+		id := aws.ToString(q.Id)
+
+		if !queryIDPattern.MatchString(id) || maxQueryIDLength < len(prefix)+len(id) {
+			return errors.NewArgumentErrorWithMessage(
+				"query Id must start with a lowercase letter and contain only letters, numbers and underscores",
+				"id",
+				id,
+			)
+		}
+	}
+
+	return nil
+}
+
+func prefixQueries(index int, queries []awstypes.MetricDataQuery) []awstypes.MetricDataQuery {
+	prefix := fmt.Sprintf("c%d_", index)
+
+	ids := make([]string, 0, len(queries))
+
+	for _, q := range queries {
+		if id := aws.ToString(q.Id); id != "" {
+			ids = append(ids, regexp.QuoteMeta(id))
+		}
+	}
+
+	pattern := regexp.MustCompile(`\b(?:` + strings.Join(ids, "|") + `)\b`)
+
+	prefixed := make([]awstypes.MetricDataQuery, 0, len(queries))
+
+	for _, q := range queries {
+		q.Id = aws.String(prefix + aws.ToString(q.Id))
+
+		if q.Expression != nil && len(ids) != 0 && !isInsightsQuery(*q.Expression) {
+			q.Expression = aws.String(prefixExpression(*q.Expression, pattern, prefix))
+		}
+
+		prefixed = append(prefixed, q)
+	}
+
+	return prefixed
+}
+
+func prefixExpression(expression string, pattern *regexp.Regexp, prefix string) string {
+	var b strings.Builder
+
+	for expression != "" {
+		start := strings.IndexAny(expression, `'"`)
+
+		if start < 0 {
+			b.WriteString(pattern.ReplaceAllString(expression, prefix+"$0"))
+
+			break
+		}
+
+		b.WriteString(pattern.ReplaceAllString(expression[:start], prefix+"$0"))
+
+		end := strings.IndexByte(expression[start+1:], expression[start])
+
+		if end < 0 {
+			b.WriteString(expression[start:])
+
+			break
+		}
+
+		b.WriteString(expression[start : start+end+2])
+
+		expression = expression[start+end+2:]
+	}
+
+	return b.String()
+}
+
+func isInsightsQuery(expression string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(expression)), "SELECT ")
+}
@@ -0,0 +1,454 @@
+package cloudwatch
+
+import (
+	goerrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func Test_prefixQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		index   int
+		queries []awstypes.MetricDataQuery
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected []awstypes.MetricDataQuery
+	}
+
+	testCases := []testCase{
+		{
+			name: "expression",
+			args: args{
+				index: 3,
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:         aws.String("m1"),
+						ReturnData: aws.Bool(false),
+					},
+					{
+						Id:         aws.String("m10"),
+						ReturnData: aws.Bool(false),
+					},
+					{
+						Id:         aws.String("e1"),
+						Expression: aws.String("100*(m1/m10)"),
+					},
+				},
+			},
+			expected: []awstypes.MetricDataQuery{
+				{
+					Id:         aws.String("c3_m1"),
+					ReturnData: aws.Bool(false),
+				},
+				{
+					Id:         aws.String("c3_m10"),
+					ReturnData: aws.Bool(false),
+				},
+				{
+					Id:         aws.String("c3_e1"),
+					Expression: aws.String("100*(c3_m1/c3_m10)"),
+				},
+			},
+		},
+		{
+			name: "function",
+			args: args{
+				index: 0,
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:         aws.String("e1"),
+						Expression: aws.String("TIME_SERIES(1)"),
+					},
+				},
+			},
+			expected: []awstypes.MetricDataQuery{
+				{
+					Id:         aws.String("c0_e1"),
+					Expression: aws.String("TIME_SERIES(1)"),
+				},
+			},
+		},
+		{
+			name: "string literal",
+			args: args{
+				index: 1,
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:         aws.String("cpu"),
+						Expression: aws.String(`SEARCH('{AWS/EC2,InstanceId} cpu "cpu"', 'Average', 300)`),
+						ReturnData: aws.Bool(false),
+					},
+					{
+						Id:         aws.String("e1"),
+						Expression: aws.String("MAX(cpu)"),
+					},
+				},
+			},
+			expected: []awstypes.MetricDataQuery{
+				{
+					Id:         aws.String("c1_cpu"),
+					Expression: aws.String(`SEARCH('{AWS/EC2,InstanceId} cpu "cpu"', 'Average', 300)`),
+					ReturnData: aws.Bool(false),
+				},
+				{
+					Id:         aws.String("c1_e1"),
+					Expression: aws.String("MAX(c1_cpu)"),
+				},
+			},
+		},
+		{
+			name: "metrics insights",
+			args: args{
+				index: 2,
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:         aws.String("avg"),
+						Expression: aws.String("SELECT avg(CPUUtilization) FROM SCHEMA(\"AWS/EC2\", InstanceId)"),
+						Period:     aws.Int32(300),
+					},
+				},
+			},
+			expected: []awstypes.MetricDataQuery{
+				{
+					Id:         aws.String("c2_avg"),
+					Expression: aws.String("SELECT avg(CPUUtilization) FROM SCHEMA(\"AWS/EC2\", InstanceId)"),
+					Period:     aws.Int32(300),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, prefixQueries(tc.args.index, tc.args.queries), "queries")
+		})
+	}
+}
+
+func Test_Batch_GetMetricSeries(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		checks  [][]awstypes.MetricDataQuery
+		factory func(*testing.T) func(types.Options) (types.Client, error)
+	}
+
+	type expected struct {
+		names [][]string
+		errs  []error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	newQueries := func(n int) []awstypes.MetricDataQuery {
+		q := make([]awstypes.MetricDataQuery, 0, n)
+
+		for i := range n {
+			q = append(q, awstypes.MetricDataQuery{
+				Id:         aws.String(fmt.Sprintf("e%d", i+1)),
+				Expression: aws.String("TIME_SERIES(1)"),
+				ReturnData: aws.Bool(i == 0),
+			})
+		}
+
+		return q
+	}
+
+	result := func(id string, label string) awstypes.MetricDataResult {
+		return awstypes.MetricDataResult{
+			Id:         aws.String(id),
+			Label:      aws.String(label),
+			Timestamps: []time.Time{now},
+			Values:     []float64{1},
+		}
+	}
+
+	testCases := []testCase{
+		{
+			name: "single request",
+			args: args{
+				checks: [][]awstypes.MetricDataQuery{
+					newQueries(1),
+					newQueries(2),
+				},
+				factory: func(t *testing.T) func(types.Options) (types.Client, error) {
+					return func(types.Options) (types.Client, error) {
+						m := &mock.CloudWatchClient{}
+
+						output := &cloudwatch.GetMetricDataOutput{
+							MetricDataResults: []awstypes.MetricDataResult{
+								result("c0_e1", "c0_e1"),
+								result("c1_e1", "CPUUtilization"),
+							},
+						}
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.MatchedBy(func(i *cloudwatch.GetMetricDataInput) bool {
+							return len(i.MetricDataQueries) == 3
+						})).Return(output, nil).Once()
+
+						t.Cleanup(func() {
+							m.AssertExpectations(t)
+						})
+
+						return m, nil
+					}
+				},
+			},
+			expected: expected{
+				names: [][]string{
+					{"e1"},
+					{"CPUUtilization"},
+				},
+				errs: []error{nil, nil},
+			},
+		},
+		{
+			name: "packed requests",
+			args: args{
+				checks: [][]awstypes.MetricDataQuery{
+					newQueries(300),
+					newQueries(300),
+					newQueries(200),
+				},
+				factory: func(t *testing.T) func(types.Options) (types.Client, error) {
+					return func(types.Options) (types.Client, error) {
+						m := &mock.CloudWatchClient{}
+
+						output1 := &cloudwatch.GetMetricDataOutput{
+							MetricDataResults: []awstypes.MetricDataResult{
+								result("c0_e1", "a"),
+								result("c2_e1", "c"),
+							},
+						}
+
+						output2 := &cloudwatch.GetMetricDataOutput{
+							MetricDataResults: []awstypes.MetricDataResult{
+								result("c1_e1", "b"),
+							},
+						}
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.MatchedBy(func(i *cloudwatch.GetMetricDataInput) bool {
+							return len(i.MetricDataQueries) == 500
+						})).Return(output1, nil).Once()
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.MatchedBy(func(i *cloudwatch.GetMetricDataInput) bool {
+							return len(i.MetricDataQueries) == 300
+						})).Return(output2, nil).Once()
+
+						t.Cleanup(func() {
+							m.AssertExpectations(t)
+						})
+
+						return m, nil
+					}
+				},
+			},
+			expected: expected{
+				names: [][]string{
+					{"a"},
+					{"b"},
+					{"c"},
+				},
+				errs: []error{nil, nil, nil},
+			},
+		},
+		{
+			name: "invalid checks",
+			args: args{
+				checks: [][]awstypes.MetricDataQuery{
+					newQueries(0),
+					newQueries(501),
+					newQueries(1),
+					{
+						{
+							Expression: aws.String("TIME_SERIES(1)"),
+						},
+					},
+					{
+						{
+							Id:         aws.String("E1"),
+							Expression: aws.String("TIME_SERIES(1)"),
+						},
+					},
+				},
+				factory: func(t *testing.T) func(types.Options) (types.Client, error) {
+					return func(types.Options) (types.Client, error) {
+						m := &mock.CloudWatchClient{}
+
+						output := &cloudwatch.GetMetricDataOutput{
+							MetricDataResults: []awstypes.MetricDataResult{
+								result("c2_e1", "c"),
+							},
+						}
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil).Once()
+
+						return m, nil
+					}
+				},
+			},
+			expected: expected{
+				names: [][]string{
+					nil,
+					nil,
+					{"c"},
+					nil,
+					nil,
+				},
+				errs: []error{&errors.ArgumentError{}, &errors.ArgumentError{}, nil, &errors.ArgumentError{}, &errors.ArgumentError{}},
+			},
+		},
+		{
+			name: "API error",
+			args: args{
+				checks: [][]awstypes.MetricDataQuery{
+					newQueries(1),
+					newQueries(1),
+				},
+				factory: func(t *testing.T) func(types.Options) (types.Client, error) {
+					return func(types.Options) (types.Client, error) {
+						m := &mock.CloudWatchClient{}
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.GetMetricDataOutput{}, goerrors.New("")).Once()
+
+						return m, nil
+					}
+				},
+			},
+			expected: expected{
+				names: [][]string{
+					nil,
+					nil,
+				},
+				errs: []error{&errors.CloudWatchError{}, &errors.CloudWatchError{}},
+			},
+		},
+		{
+			name: "forbidden result",
+			args: args{
+				checks: [][]awstypes.MetricDataQuery{
+					newQueries(1),
+					newQueries(1),
+					newQueries(1),
+				},
+				factory: func(t *testing.T) func(types.Options) (types.Client, error) {
+					return func(types.Options) (types.Client, error) {
+						m := &mock.CloudWatchClient{}
+
+						forbidden := result("c1_e1", "b")
+
+						forbidden.StatusCode = awstypes.StatusCodeForbidden
+						forbidden.Messages = []awstypes.MessageData{
+							{
+								Code:  aws.String("Forbidden"),
+								Value: aws.String("not authorized to access the metric"),
+							},
+						}
+
+						output := &cloudwatch.GetMetricDataOutput{
+							MetricDataResults: []awstypes.MetricDataResult{
+								result("c0_e1", "a"),
+								forbidden,
+								result("c2_e1", "c"),
+							},
+						}
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil).Once()
+
+						return m, nil
+					}
+				},
+			},
+			expected: expected{
+				names: [][]string{
+					{"a"},
+					nil,
+					{"c"},
+				},
+				errs: []error{nil, &errors.MetricDataError{}, nil},
+			},
+		},
+		{
+			name: "missing results",
+			args: args{
+				checks: [][]awstypes.MetricDataQuery{
+					newQueries(1),
+					newQueries(1),
+				},
+				factory: func(t *testing.T) func(types.Options) (types.Client, error) {
+					return func(types.Options) (types.Client, error) {
+						m := &mock.CloudWatchClient{}
+
+						output := &cloudwatch.GetMetricDataOutput{
+							MetricDataResults: []awstypes.MetricDataResult{
+								result("c1_e1", "b"),
+							},
+						}
+
+						m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil).Once()
+
+						return m, nil
+					}
+				},
+			},
+			expected: expected{
+				names: [][]string{
+					nil,
+					{"b"},
+				},
+				errs: []error{&errors.CloudWatchError{}, nil},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+			helper.SetCloudWatchClientFactory(t, tc.args.factory(t))
+
+			b := NewBatch(10, tc.args.checks, types.Options{}, 5)
+
+			results := b.GetMetricSeries(now)
+
+			assert.Len(results, len(tc.args.checks), "results")
+
+			for i, r := range results {
+				var names []string
+
+				for _, s := range r.Series {
+					names = append(names, s.Name())
+				}
+
+				assert.Equal(tc.expected.names[i], names, "names")
+
+				if tc.expected.errs[i] != nil {
+					assert.ErrorAs(r.Err, tc.expected.errs[i], "is error")
+				} else {
+					assert.Nil(r.Err, "is not error")
+				}
+			}
+		})
+	}
+}
@@ -2,27 +2,121 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
 )
 
-func New() (types.Client, error) {
+func New(options types.Options) (types.Client, error) {
 	log.V(3).Trace().
 		Str("package", "cloudwatch").
+		Str("region", options.Region).
+		Str("profile", options.Profile).
+		Str("endpoint_url", options.EndpointURL).
+		Int("retries", options.Retries).
 		Msg("creating CloudWatch API client")
 
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRetryer(func() aws.Retryer {
-		return aws.NopRetryer{}
-	}))
+	cfg, err := loadConfig(options)
 
 	if err != nil {
-		return nil, errors.NewCloudWatchError(err)
+		return nil, err
 	}
 
-	return cloudwatch.NewFromConfig(cfg), nil
+	return cloudwatch.NewFromConfig(cfg, func(o *cloudwatch.Options) {
+		if options.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(options.EndpointURL)
+		}
+	}), nil
+}
+
+func NewLogs(options types.Options) (types.LogsClient, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Str("region", options.Region).
+		Str("profile", options.Profile).
+		Str("endpoint_url", options.EndpointURL).
+		Int("retries", options.Retries).
+		Msg("creating CloudWatch Logs API client")
+
+	cfg, err := loadConfig(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudwatchlogs.NewFromConfig(cfg, func(o *cloudwatchlogs.Options) {
+		if options.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(options.EndpointURL)
+		}
+	}), nil
+}
+
+func loadConfig(options types.Options) (aws.Config, error) {
+	loadOptions := []func(*config.LoadOptions) error{
+		config.WithRetryer(func() aws.Retryer {
+			return newRetryer(options.Retries)
+		}),
+	}
+
+	if options.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(options.Region))
+	}
+
+	if options.Profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(options.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
+
+	if err != nil {
+		return aws.Config{}, errors.NewCloudWatchError(err)
+	}
+
+	if options.RoleARN != "" {
+		if cfg.Credentials, err = assumeRole(cfg, options); err != nil {
+			return aws.Config{}, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func assumeRole(cfg aws.Config, options types.Options) (aws.CredentialsProvider, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Str("role_arn", options.RoleARN).
+		Str("role_session_name", options.RoleSessionName).
+		Bool("external_id", options.ExternalID != "").
+		Int("timeout", options.Timeout).
+		Msg("assuming IAM role")
+
+	provider := aws.NewCredentialsCache(
+		stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), options.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			if options.ExternalID != "" {
+				o.ExternalID = aws.String(options.ExternalID)
+			}
+
+			if options.RoleSessionName != "" {
+				o.RoleSessionName = options.RoleSessionName
+			}
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(options.Timeout)*time.Second)
+
+	defer cancel()
+
+	if _, err := provider.Retrieve(ctx); err != nil {
+		return nil, errors.NewAssumeRoleError(err, options.RoleARN)
+	}
+
+	return provider, nil
 }
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_New(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		region       string
+		baseEndpoint *string
+		err          error
+	}
+
+	type testCase struct {
+		name     string
+		args     types.Options
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "default",
+			args: types.Options{},
+			expected: expected{
+				region:       "",
+				baseEndpoint: nil,
+				err:          nil,
+			},
+		},
+		{
+			name: "region and endpoint",
+			args: types.Options{
+				Region:      "ap-northeast-1",
+				EndpointURL: "http://localhost:4566",
+			},
+			expected: expected{
+				region:       "ap-northeast-1",
+				baseEndpoint: aws.String("http://localhost:4566"),
+				err:          nil,
+			},
+		},
+		{
+			name: "unknown profile",
+			args: types.Options{
+				Profile: "UNKNOWN-PROFILE",
+			},
+			expected: expected{
+				err: &errors.CloudWatchError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			dir := t.TempDir()
+
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+			t.Setenv("AWS_REGION", "")
+			t.Setenv("AWS_DEFAULT_REGION", "")
+			t.Setenv("AWS_PROFILE", "")
+
+			c, err := New(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				options := c.(*cloudwatch.Client).Options()
+
+				assert.Equal(tc.expected.region, options.Region, "region")
+				assert.Equal(tc.expected.baseEndpoint, options.BaseEndpoint, "base endpoint")
+			}
+		})
+	}
+}
+
+func Test_NewLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		region       string
+		baseEndpoint *string
+		err          error
+	}
+
+	type testCase struct {
+		name     string
+		args     types.Options
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "region and endpoint",
+			args: types.Options{
+				Region:      "ap-northeast-1",
+				EndpointURL: "http://localhost:4566",
+			},
+			expected: expected{
+				region:       "ap-northeast-1",
+				baseEndpoint: aws.String("http://localhost:4566"),
+				err:          nil,
+			},
+		},
+		{
+			name: "unknown profile",
+			args: types.Options{
+				Profile: "UNKNOWN-PROFILE",
+			},
+			expected: expected{
+				err: &errors.CloudWatchError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			dir := t.TempDir()
+
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+			t.Setenv("AWS_REGION", "")
+			t.Setenv("AWS_DEFAULT_REGION", "")
+			t.Setenv("AWS_PROFILE", "")
+
+			c, err := NewLogs(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				options := c.(*cloudwatchlogs.Client).Options()
+
+				assert.Equal(tc.expected.region, options.Region, "region")
+				assert.Equal(tc.expected.baseEndpoint, options.BaseEndpoint, "base endpoint")
+			}
+		})
+	}
+}
+
+func Test_New_assumeRole(t *testing.T) {
+	assert := assert.New(t)
+
+	assumed := `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASSUMED-ACCESS-KEY</AccessKeyId>
+      <SecretAccessKey>ASSUMED-SECRET-KEY</SecretAccessKey>
+      <SessionToken>ASSUMED-SESSION-TOKEN</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+	type args struct {
+		options    types.Options
+		statusCode int
+		body       string
+		delay      time.Duration
+	}
+
+	type expected struct {
+		form        map[string]string
+		accessKeyID string
+		err         error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "assumed",
+			args: args{
+				options: types.Options{
+					Region:          "ap-northeast-1",
+					RoleARN:         "arn:aws:iam::123456789012:role/monitoring",
+					ExternalID:      "EXTERNAL-ID",
+					RoleSessionName: "check_cloudwatch",
+					Timeout:         5,
+				},
+				statusCode: http.StatusOK,
+				body:       assumed,
+			},
+			expected: expected{
+				form: map[string]string{
+					"Action":          "AssumeRole",
+					"RoleArn":         "arn:aws:iam::123456789012:role/monitoring",
+					"ExternalId":      "EXTERNAL-ID",
+					"RoleSessionName": "check_cloudwatch",
+				},
+				accessKeyID: "ASSUMED-ACCESS-KEY",
+				err:         nil,
+			},
+		},
+		{
+			name: "access denied",
+			args: args{
+				options: types.Options{
+					Region:          "ap-northeast-1",
+					RoleARN:         "arn:aws:iam::123456789012:role/monitoring",
+					RoleSessionName: "check_cloudwatch",
+					Timeout:         5,
+				},
+				statusCode: http.StatusForbidden,
+				body: `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>AccessDenied</Code>
+    <Message>not authorized to perform sts:AssumeRole</Message>
+  </Error>
+</ErrorResponse>`,
+			},
+			expected: expected{
+				err: &errors.AssumeRoleError{},
+			},
+		},
+		{
+			name: "timed out",
+			args: args{
+				options: types.Options{
+					Region:          "ap-northeast-1",
+					RoleARN:         "arn:aws:iam::123456789012:role/monitoring",
+					RoleSessionName: "check_cloudwatch",
+					Timeout:         1,
+				},
+				statusCode: http.StatusOK,
+				body:       assumed,
+				delay:      2 * time.Second,
+			},
+			expected: expected{
+				err: &errors.AssumeRoleError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			form := map[string]string{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tc.args.delay)
+
+				_ = r.ParseForm()
+
+				for k := range r.PostForm {
+					form[k] = r.PostForm.Get(k)
+				}
+
+				w.Header().Set("Content-Type", "text/xml")
+				w.WriteHeader(tc.args.statusCode)
+
+				_, _ = w.Write([]byte(tc.args.body))
+			}))
+
+			defer server.Close()
+
+			dir := t.TempDir()
+
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+			t.Setenv("AWS_PROFILE", "")
+			t.Setenv("AWS_ACCESS_KEY_ID", "ACCESS-KEY")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET-KEY")
+			t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+
+			c, err := New(tc.args.options)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				for k, v := range tc.expected.form {
+					assert.Equal(v, form[k], k)
+				}
+
+				credentials, err := c.(*cloudwatch.Client).Options().Credentials.Retrieve(context.Background())
+
+				assert.Nil(err, "is not error")
+				assert.Equal(tc.expected.accessKeyID, credentials.AccessKeyID, "access key ID")
+			}
+		})
+	}
+}
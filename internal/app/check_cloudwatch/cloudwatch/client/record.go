@@ -0,0 +1,192 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type Recording struct {
+	Time           time.Time                `json:"time"`
+	GetMetricData  []MetricDataExchange     `json:"getMetricData"`
+	DescribeAlarms []DescribeAlarmsExchange `json:"describeAlarms"`
+}
+
+type MetricDataExchange struct {
+	Input  *cloudwatch.GetMetricDataInput  `json:"input"`
+	Output *cloudwatch.GetMetricDataOutput `json:"output"`
+}
+
+type DescribeAlarmsExchange struct {
+	Input  *cloudwatch.DescribeAlarmsInput  `json:"input"`
+	Output *cloudwatch.DescribeAlarmsOutput `json:"output"`
+}
+
+type recorder struct {
+	client    types.Client
+	path      string
+	recording Recording
+}
+
+type replayer struct {
+	recording Recording
+}
+
+func NewRecorder(path string, factory func(types.Options) (types.Client, error)) func(types.Options) (types.Client, error) {
+	return func(options types.Options) (types.Client, error) {
+		c, err := factory(options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Str("path", path).
+			Msg("recording API responses")
+
+		return &recorder{
+			client: c,
+			path:   path,
+			recording: Recording{
+				GetMetricData:  []MetricDataExchange{},
+				DescribeAlarms: []DescribeAlarmsExchange{},
+			},
+		}, nil
+	}
+}
+
+func (r *recorder) GetMetricData(
+	ctx context.Context,
+	params *cloudwatch.GetMetricDataInput,
+	optFns ...func(*cloudwatch.Options),
+) (*cloudwatch.GetMetricDataOutput, error) {
+	output, err := r.client.GetMetricData(ctx, params, optFns...)
+
+	if err != nil {
+		return output, err
+	}
+
+	if r.recording.Time.IsZero() {
+		r.recording.Time = aws.ToTime(params.EndTime)
+	}
+
+	r.recording.GetMetricData = append(r.recording.GetMetricData, MetricDataExchange{Input: params, Output: output})
+
+	return output, r.save()
+}
+
+func (r *recorder) DescribeAlarms(
+	ctx context.Context,
+	params *cloudwatch.DescribeAlarmsInput,
+	optFns ...func(*cloudwatch.Options),
+) (*cloudwatch.DescribeAlarmsOutput, error) {
+	output, err := r.client.DescribeAlarms(ctx, params, optFns...)
+
+	if err != nil {
+		return output, err
+	}
+
+	if r.recording.Time.IsZero() {
+		r.recording.Time = time.Now()
+	}
+
+	r.recording.DescribeAlarms = append(r.recording.DescribeAlarms, DescribeAlarmsExchange{Input: params, Output: output})
+
+	return output, r.save()
+}
+
+func (r *recorder) save() error {
+	b, err := json.MarshalIndent(r.recording, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, b, 0o600)
+}
+
+func LoadRecording(path string) (Recording, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Str("path", path).
+		Msg("loading recorded API responses")
+
+	var recording Recording
+
+	b, err := os.ReadFile(path)
+
+	if err != nil {
+		return recording, errors.NewArgumentErrorWithError(err, "replay", path)
+	}
+
+	if err := json.Unmarshal(b, &recording); err != nil {
+		return recording, errors.NewArgumentErrorWithError(err, "replay", path)
+	}
+
+	return recording, nil
+}
+
+func NewReplayer(recording Recording) func(types.Options) (types.Client, error) {
+	return func(types.Options) (types.Client, error) {
+		return &replayer{
+			recording: recording,
+		}, nil
+	}
+}
+
+func (r *replayer) GetMetricData(
+	ctx context.Context,
+	params *cloudwatch.GetMetricDataInput,
+	optFns ...func(*cloudwatch.Options),
+) (*cloudwatch.GetMetricDataOutput, error) {
+	if len(r.recording.GetMetricData) == 0 {
+		return nil, goerrors.New("no recorded GetMetricData response left to replay")
+	}
+
+	e := r.recording.GetMetricData[0]
+
+	r.recording.GetMetricData = r.recording.GetMetricData[1:]
+
+	if !sameJSON(e.Input.MetricDataQueries, params.MetricDataQueries) || aws.ToString(e.Input.NextToken) != aws.ToString(params.NextToken) {
+		return nil, goerrors.New("GetMetricData request does not match the recording")
+	}
+
+	return e.Output, nil
+}
+
+func (r *replayer) DescribeAlarms(
+	ctx context.Context,
+	params *cloudwatch.DescribeAlarmsInput,
+	optFns ...func(*cloudwatch.Options),
+) (*cloudwatch.DescribeAlarmsOutput, error) {
+	if len(r.recording.DescribeAlarms) == 0 {
+		return nil, goerrors.New("no recorded DescribeAlarms response left to replay")
+	}
+
+	e := r.recording.DescribeAlarms[0]
+
+	r.recording.DescribeAlarms = r.recording.DescribeAlarms[1:]
+
+	if !sameJSON(e.Input, params) {
+		return nil, goerrors.New("DescribeAlarms request does not match the recording")
+	}
+
+	return e.Output, nil
+}
+
+func sameJSON(a any, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	goerrors "errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func Test_recordAndReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	queries := []awstypes.MetricDataQuery{
+		{
+			Id:         aws.String("e1"),
+			Expression: aws.String("TIME_SERIES(1)"),
+		},
+	}
+
+	metricDataInput := &cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(now.Add(-10 * time.Minute)),
+		EndTime:           aws.Time(now),
+		MetricDataQueries: queries,
+	}
+
+	metricDataOutput := &cloudwatch.GetMetricDataOutput{
+		MetricDataResults: []awstypes.MetricDataResult{
+			{
+				Id:         aws.String("e1"),
+				Label:      aws.String("e1"),
+				StatusCode: awstypes.StatusCodeComplete,
+				Timestamps: []time.Time{now},
+				Values:     []float64{1.5},
+			},
+		},
+	}
+
+	alarmsInput := &cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []string{"web-cpu"},
+	}
+
+	alarmsOutput := &cloudwatch.DescribeAlarmsOutput{
+		MetricAlarms: []awstypes.MetricAlarm{
+			{
+				AlarmName:  aws.String("web-cpu"),
+				StateValue: awstypes.StateValueAlarm,
+			},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+
+	m := &mock.CloudWatchClient{}
+
+	m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(metricDataOutput, nil).Once()
+	m.On("DescribeAlarms", testifymock.Anything, testifymock.Anything).Return(alarmsOutput, nil).Once()
+
+	recorder, err := NewRecorder(path, func(types.Options) (types.Client, error) {
+		return m, nil
+	})(types.Options{})
+
+	assert.Nil(err, "is not error")
+
+	_, err = recorder.GetMetricData(context.Background(), metricDataInput)
+
+	assert.Nil(err, "is not error")
+
+	_, err = recorder.DescribeAlarms(context.Background(), alarmsInput)
+
+	assert.Nil(err, "is not error")
+
+	m.AssertExpectations(t)
+
+	recording, err := LoadRecording(path)
+
+	assert.Nil(err, "is not error")
+	assert.True(now.Equal(recording.Time), "time")
+
+	replayer, err := NewReplayer(recording)(types.Options{})
+
+	assert.Nil(err, "is not error")
+
+	replayedMetricData, err := replayer.GetMetricData(context.Background(), &cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(now.Add(time.Hour)),
+		EndTime:           aws.Time(now.Add(time.Hour)),
+		MetricDataQueries: queries,
+	})
+
+	assert.Nil(err, "is not error")
+	assert.Equal([]float64{1.5}, replayedMetricData.MetricDataResults[0].Values, "values")
+	assert.True(now.Equal(replayedMetricData.MetricDataResults[0].Timestamps[0]), "timestamps")
+	assert.Equal(awstypes.StatusCodeComplete, replayedMetricData.MetricDataResults[0].StatusCode, "status code")
+
+	replayedAlarms, err := replayer.DescribeAlarms(context.Background(), alarmsInput)
+
+	assert.Nil(err, "is not error")
+	assert.Equal(awstypes.StateValueAlarm, replayedAlarms.MetricAlarms[0].StateValue, "state")
+
+	_, err = replayer.GetMetricData(context.Background(), metricDataInput)
+
+	assert.NotNil(err, "no response left")
+}
+
+func Test_recorder_error(t *testing.T) {
+	assert := assert.New(t)
+
+	helper.SetLogOutputDiscard(t)
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+
+	m := &mock.CloudWatchClient{}
+
+	m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(&cloudwatch.GetMetricDataOutput{}, goerrors.New("")).Once()
+
+	recorder, _ := NewRecorder(path, func(types.Options) (types.Client, error) {
+		return m, nil
+	})(types.Options{})
+
+	_, err := recorder.GetMetricData(context.Background(), &cloudwatch.GetMetricDataInput{})
+
+	assert.NotNil(err, "is error")
+
+	_, err = LoadRecording(path)
+
+	assert.ErrorAs(err, &errors.ArgumentError{}, "nothing recorded")
+}
+
+func Test_replayer_mismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	recording := Recording{
+		GetMetricData: []MetricDataExchange{
+			{
+				Input: &cloudwatch.GetMetricDataInput{
+					MetricDataQueries: []awstypes.MetricDataQuery{
+						{
+							Id:         aws.String("e1"),
+							Expression: aws.String("TIME_SERIES(1)"),
+						},
+					},
+				},
+				Output: &cloudwatch.GetMetricDataOutput{},
+			},
+		},
+	}
+
+	replayer, _ := NewReplayer(recording)(types.Options{})
+
+	_, err := replayer.GetMetricData(context.Background(), &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: []awstypes.MetricDataQuery{
+			{
+				Id:         aws.String("e1"),
+				Expression: aws.String("TIME_SERIES(2)"),
+			},
+		},
+	})
+
+	assert.NotNil(err, "is error")
+
+	_, err = replayer.DescribeAlarms(context.Background(), &cloudwatch.DescribeAlarmsInput{})
+
+	assert.NotNil(err, "is error")
+}
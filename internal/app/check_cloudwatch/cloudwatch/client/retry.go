@@ -0,0 +1,71 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+var throttleErrorCodes = map[string]struct{}{
+	"Throttling":               {},
+	"ThrottlingException":      {},
+	"TooManyRequests":          {},
+	"TooManyRequestsException": {},
+	"RequestLimitExceeded":     {},
+}
+
+type loggingRetryer struct {
+	aws.RetryerV2
+}
+
+func newRetryer(retries int) aws.Retryer {
+	if retries <= 0 {
+		return aws.NopRetryer{}
+	}
+
+	return loggingRetryer{
+		RetryerV2: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = retries + 1
+			o.RateLimiter = ratelimit.None
+			o.Retryables = []retry.IsErrorRetryable{
+				retry.NoRetryCanceledError{},
+				retry.IsErrorRetryableFunc(isServerError),
+				retry.RetryableErrorCode{
+					Codes: throttleErrorCodes,
+				},
+			}
+		}),
+	}
+}
+
+func (r loggingRetryer) RetryDelay(attempt int, err error) (time.Duration, error) {
+	delay, delayErr := r.RetryerV2.RetryDelay(attempt, err)
+
+	log.V(2).Debug().
+		Str("package", "cloudwatch").
+		Int("attempt", attempt).
+		Int("max_attempts", r.MaxAttempts()).
+		Dur("delay", delay).
+		Err(err).
+		Msg("retrying API request")
+
+	return delay, delayErr
+}
+
+func isServerError(err error) aws.Ternary {
+	var v interface{ HTTPStatusCode() int }
+
+	if !errors.As(err, &v) {
+		return aws.UnknownTernary
+	}
+
+	if v.HTTPStatusCode() < 500 {
+		return aws.UnknownTernary
+	}
+
+	return aws.TrueTernary
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResponseError(statusCode int, err error) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{
+				Response: &http.Response{
+					StatusCode: statusCode,
+				},
+			},
+			Err: err,
+		},
+	}
+}
+
+func Test_newRetryer(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		retries int
+		err     error
+	}
+
+	type expected struct {
+		maxAttempts int
+		retryable   bool
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "no retries",
+			args: args{
+				retries: 0,
+				err:     newResponseError(400, &smithy.GenericAPIError{Code: "Throttling"}),
+			},
+			expected: expected{
+				maxAttempts: 1,
+				retryable:   false,
+			},
+		},
+		{
+			name: "throttling",
+			args: args{
+				retries: 3,
+				err:     newResponseError(400, &smithy.GenericAPIError{Code: "Throttling"}),
+			},
+			expected: expected{
+				maxAttempts: 4,
+				retryable:   true,
+			},
+		},
+		{
+			name: "too many requests",
+			args: args{
+				retries: 3,
+				err:     newResponseError(429, &smithy.GenericAPIError{Code: "TooManyRequests"}),
+			},
+			expected: expected{
+				maxAttempts: 4,
+				retryable:   true,
+			},
+		},
+		{
+			name: "server error",
+			args: args{
+				retries: 2,
+				err:     newResponseError(500, &smithy.GenericAPIError{Code: "InternalServiceError"}),
+			},
+			expected: expected{
+				maxAttempts: 3,
+				retryable:   true,
+			},
+		},
+		{
+			name: "service unavailable",
+			args: args{
+				retries: 2,
+				err:     newResponseError(503, &smithy.GenericAPIError{Code: "ServiceUnavailable"}),
+			},
+			expected: expected{
+				maxAttempts: 3,
+				retryable:   true,
+			},
+		},
+		{
+			name: "client error",
+			args: args{
+				retries: 2,
+				err:     newResponseError(400, &smithy.GenericAPIError{Code: "InvalidParameterValue"}),
+			},
+			expected: expected{
+				maxAttempts: 3,
+				retryable:   false,
+			},
+		},
+		{
+			name: "canceled",
+			args: args{
+				retries: 2,
+				err:     &aws.RequestCanceledError{Err: context.DeadlineExceeded},
+			},
+			expected: expected{
+				maxAttempts: 3,
+				retryable:   false,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newRetryer(tc.args.retries)
+
+			assert.Equal(tc.expected.maxAttempts, r.MaxAttempts(), "max attempts")
+			assert.Equal(tc.expected.retryable, r.IsErrorRetryable(tc.args.err), "retryable")
+		})
+	}
+}
+
+func Test_New_retries(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     int
+		expected int
+	}
+
+	testCases := []testCase{
+		{
+			name:     "no retries",
+			args:     0,
+			expected: 1,
+		},
+		{
+			name:     "retries",
+			args:     2,
+			expected: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			requests := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+
+			defer server.Close()
+
+			dir := t.TempDir()
+
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+			t.Setenv("AWS_PROFILE", "")
+			t.Setenv("AWS_ACCESS_KEY_ID", "ACCESS-KEY")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET-KEY")
+
+			c, err := New(types.Options{
+				Region:      "ap-northeast-1",
+				EndpointURL: server.URL,
+				Retries:     tc.args,
+			})
+
+			assert.Nil(err, "is not error")
+
+			_, err = c.GetMetricData(context.Background(), &cloudwatch.GetMetricDataInput{
+				StartTime: aws.Time(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+				EndTime:   aws.Time(time.Date(2022, 1, 1, 1, 0, 0, 0, time.UTC)),
+				MetricDataQueries: []awstypes.MetricDataQuery{
+					{
+						Id:         aws.String("m1"),
+						Expression: aws.String("SELECT AVG(CPUUtilization) FROM \"AWS/EC2\""),
+						Period:     aws.Int32(300),
+					},
+				},
+			})
+
+			assert.NotNil(err, "is error")
+			assert.Equal(tc.expected, requests, "requests")
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 )
 
 type Client interface {
@@ -12,4 +13,43 @@ type Client interface {
 		params *cloudwatch.GetMetricDataInput,
 		optFns ...func(*cloudwatch.Options),
 	) (*cloudwatch.GetMetricDataOutput, error)
+
+	DescribeAlarms(
+		ctx context.Context,
+		params *cloudwatch.DescribeAlarmsInput,
+		optFns ...func(*cloudwatch.Options),
+	) (*cloudwatch.DescribeAlarmsOutput, error)
+}
+
+type LogsClient interface {
+	StartQuery(
+		ctx context.Context,
+		params *cloudwatchlogs.StartQueryInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.StartQueryOutput, error)
+
+	GetQueryResults(
+		ctx context.Context,
+		params *cloudwatchlogs.GetQueryResultsInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.GetQueryResultsOutput, error)
+
+	StopQuery(
+		ctx context.Context,
+		params *cloudwatchlogs.StopQueryInput,
+		optFns ...func(*cloudwatchlogs.Options),
+	) (*cloudwatchlogs.StopQueryOutput, error)
+}
+
+type Options struct {
+	Region          string
+	Profile         string
+	EndpointURL     string
+	RoleARN         string
+	ExternalID      string
+	RoleSessionName string
+	Retries         int
+	Timeout         int
+	CacheDir        string
+	CacheTTL        int
 }
@@ -3,11 +3,18 @@ package cloudwatch
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cache"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/container"
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
@@ -16,52 +23,155 @@ import (
 
 type CloudWatch struct {
 	client   types.Client
+	options  types.Options
 	duration int
 	queries  []awstypes.MetricDataQuery
 	timeout  int
+	cache    *cache.Cache
+	cacheKey string
 	result   *cloudwatch.GetMetricDataOutput
 }
 
-func New(duration int, queriesStr string, timeout int) (CloudWatch, error) {
-	client, err := container.GetCloudWatchClient()
-
-	if err != nil {
-		return CloudWatch{}, err
-	}
-
-	queries, err := parseQueries(queriesStr)
+func New(
+	duration int, queriesStr string, vars []string, metric Metric,
+	options types.Options, timeout int,
+) (CloudWatch, error) {
+	queries, err := buildMetricDataQueries(queriesStr, vars, metric)
 
 	if err != nil {
 		return CloudWatch{}, err
 	}
 
 	return CloudWatch{
-		client:   client,
+		options:  options,
 		duration: duration,
 		queries:  queries,
 		timeout:  timeout,
+		cache:    cache.New(options.CacheDir, options.CacheTTL, time.Duration(timeout)*time.Second/2),
+		cacheKey: metricDataCacheKey(duration, queries, options),
 	}, nil
 }
 
-func parseQueries(queries string) ([]awstypes.MetricDataQuery, error) {
+func BuildInput(duration int, queriesStr string, vars []string, metric Metric, now time.Time) (*cloudwatch.GetMetricDataInput, error) {
+	queries, err := buildMetricDataQueries(queriesStr, vars, metric)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricDataInput(duration, queries, now), nil
+}
+
+func buildMetricDataQueries(queriesStr string, vars []string, metric Metric) ([]awstypes.MetricDataQuery, error) {
+	switch {
+	case metric.SQL != "":
+		return buildSQLQueries(metric)
+	case metric.MetricName != "":
+		return buildQueries(metric)
+	default:
+		return parseVarsAndQueries(queriesStr, vars)
+	}
+}
+
+func newMetricDataInput(duration int, queries []awstypes.MetricDataQuery, now time.Time) *cloudwatch.GetMetricDataInput {
+	return &cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(now.Add(-1 * time.Duration(duration) * time.Minute)),
+		EndTime:           aws.Time(now),
+		MetricDataQueries: queries,
+	}
+}
+
+func metricDataCacheKey(duration int, queries []awstypes.MetricDataQuery, options types.Options) string {
+	return cache.Key(struct {
+		Duration    int
+		Queries     []awstypes.MetricDataQuery
+		Region      string
+		Profile     string
+		EndpointURL string
+		RoleARN     string
+	}{
+		Duration:    duration,
+		Queries:     queries,
+		Region:      options.Region,
+		Profile:     options.Profile,
+		EndpointURL: options.EndpointURL,
+		RoleARN:     options.RoleARN,
+	})
+}
+
+func parseVarsAndQueries(queries string, vars []string) ([]awstypes.MetricDataQuery, error) {
+	v, err := parseVars(vars)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseQueries(queries, v)
+}
+
+func parseQueries(queries string, vars map[string]string) ([]awstypes.MetricDataQuery, error) {
 	var q []awstypes.MetricDataQuery
 
 	log.V(3).Trace().
 		Str("package", "cloudwatch").
 		Msg("parsing API queries")
 
-	if err := json.Unmarshal([]byte(queries), &q); err != nil {
+	b, err := readQueries(queries, "queries")
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = renderQueries(b, vars)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &q); err != nil {
 		return nil, errors.NewArgumentErrorWithError(err, "queries", queries)
 	}
 
 	log.V(3).Trace().
 		Str("package", "cloudwatch").
-		RawJSON("queries", []byte(queries)).
+		RawJSON("queries", b).
 		Send()
 
 	return q, nil
 }
 
+func readQueries(queries string, key string) ([]byte, error) {
+	switch {
+	case queries == "-":
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Msg("reading API queries from stdin")
+
+		b, err := io.ReadAll(container.GetInputIO())
+
+		if err != nil {
+			return nil, errors.NewArgumentErrorWithError(err, key, queries)
+		}
+
+		return b, nil
+	case strings.HasPrefix(queries, "@"):
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Str("path", queries[1:]).
+			Msg("reading API queries from file")
+
+		b, err := os.ReadFile(queries[1:])
+
+		if err != nil {
+			return nil, errors.NewArgumentErrorWithError(err, key, queries)
+		}
+
+		return b, nil
+	default:
+		return []byte(queries), nil
+	}
+}
+
 func (c *CloudWatch) GetMetricValues(now time.Time) ([]float64, error) {
 	log.V(3).Trace().
 		Str("package", "cloudwatch").
@@ -73,31 +183,134 @@ func (c *CloudWatch) GetMetricValues(now time.Time) ([]float64, error) {
 
 	c.printResult()
 
+	if err := c.checkStatusCodes(c.result.MetricDataResults); err != nil {
+		return []float64{}, err
+	}
+
 	return c.result.MetricDataResults[0].Values, nil
 }
 
+func (c *CloudWatch) GetMetricSeries(now time.Time) ([]Series, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Msg("calling GetMetricData API")
+
+	if err := c.getMetricData(now); err != nil {
+		return []Series{}, err
+	}
+
+	c.printResult()
+
+	if err := c.checkStatusCodes(c.result.MetricDataResults); err != nil {
+		return []Series{}, err
+	}
+
+	if len(c.result.MetricDataResults) == 0 {
+		return []Series{}, errors.NewCloudWatchError(goerrors.New("no metric data results returned"))
+	}
+
+	series := make([]Series, 0, len(c.result.MetricDataResults))
+
+	for _, r := range c.result.MetricDataResults {
+		series = append(series, c.newSeries(r))
+	}
+
+	return series, nil
+}
+
+func (c CloudWatch) newSeries(r awstypes.MetricDataResult) Series {
+	s := newSeries(r)
+
+	s.Period = c.period(s)
+	s.Unit = c.unit(s)
+
+	return s
+}
+
 func (c CloudWatch) LatestValue() (metricName string, value float64, timestamp time.Time) {
-	m := c.result.MetricDataResults[0]
+	s := newSeries(c.result.MetricDataResults[0])
 
-	if m.Label == nil {
-		metricName = *m.Id
-	} else {
-		metricName = *m.Label
+	value, timestamp = s.Latest()
+
+	return s.Name(), value, timestamp
+}
+
+func (c CloudWatch) period(s Series) int32 {
+	for _, q := range c.queries {
+		if aws.ToString(q.Id) != s.Id {
+			continue
+		}
+
+		if q.Period != nil {
+			return *q.Period
+		}
+
+		if q.MetricStat != nil && q.MetricStat.Period != nil {
+			return *q.MetricStat.Period
+		}
 	}
 
-	if len(m.Values) != 0 {
-		value = m.Values[0]
+	return inferPeriod(s.Timestamps)
+}
+
+func (c CloudWatch) unit(s Series) string {
+	for _, q := range c.queries {
+		if aws.ToString(q.Id) == s.Id && q.MetricStat != nil {
+			return string(q.MetricStat.Unit)
+		}
 	}
 
-	if len(m.Timestamps) != 0 {
-		timestamp = m.Timestamps[0]
+	return ""
+}
+
+func (c CloudWatch) Warnings() []string {
+	if c.result == nil {
+		return []string{}
+	}
+
+	warnings := formatMessages(c.result.Messages)
+
+	for _, r := range c.result.MetricDataResults {
+		name := newSeries(r).Name()
+
+		if r.StatusCode == awstypes.StatusCodePartialData {
+			warnings = append(warnings, fmt.Sprintf("partial data returned for %s", name))
+		}
+
+		for _, m := range formatMessages(r.Messages) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", name, m))
+		}
 	}
 
-	return
+	return warnings
 }
 
 func (c *CloudWatch) getMetricData(now time.Time) error {
-	startTime := now.Add(-1 * time.Duration(c.duration) * time.Minute)
+	result, err := cache.Fetch(c.cache, c.cacheKey, now, func() (*cloudwatch.GetMetricDataOutput, error) {
+		if c.client == nil {
+			client, err := container.GetCloudWatchClient(c.options)
+
+			if err != nil {
+				return nil, err
+			}
+
+			c.client = client
+		}
+
+		return c.requestMetricData(now)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	c.result = result
+
+	return nil
+}
+
+func (c CloudWatch) requestMetricData(now time.Time) (*cloudwatch.GetMetricDataOutput, error) {
+	input := newMetricDataInput(c.duration, c.queries, now)
 
 	ctx, cancel := context.WithDeadline(
 		context.Background(),
@@ -108,26 +321,106 @@ func (c *CloudWatch) getMetricData(now time.Time) error {
 
 	log.V(3).Trace().
 		Str("package", "cloudwatch").
-		Time("start_time", startTime).
-		Time("end_time", now).
+		Time("start_time", *input.StartTime).
+		Time("end_time", *input.EndTime).
 		Int("timeout", c.timeout).
 		Msg("API parameters")
 
-	result, err := c.client.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
-		StartTime:         aws.Time(startTime),
-		EndTime:           aws.Time(now),
-		MetricDataQueries: c.queries,
-	})
+	result := &cloudwatch.GetMetricDataOutput{}
 
-	if err != nil {
-		return errors.NewCloudWatchError(err)
+	for page := 1; ; page++ {
+		output, err := c.client.GetMetricData(ctx, input)
+
+		if err != nil {
+			return nil, errors.NewCloudWatchError(err)
+		}
+
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Int("page", page).
+			Bool("has_next_page", output.NextToken != nil).
+			Msg("API page retrieved")
+
+		mergeMetricDataOutput(result, output)
+
+		if output.NextToken == nil {
+			break
+		}
+
+		input = &cloudwatch.GetMetricDataInput{
+			StartTime:         input.StartTime,
+			EndTime:           input.EndTime,
+			MetricDataQueries: input.MetricDataQueries,
+			NextToken:         output.NextToken,
+		}
 	}
 
-	c.result = result
+	return result, nil
+}
+
+func mergeMetricDataOutput(dst *cloudwatch.GetMetricDataOutput, src *cloudwatch.GetMetricDataOutput) {
+	dst.Messages = append(dst.Messages, src.Messages...)
+
+	for _, r := range src.MetricDataResults {
+		i := slices.IndexFunc(dst.MetricDataResults, func(d awstypes.MetricDataResult) bool {
+			return aws.ToString(d.Id) == aws.ToString(r.Id) && aws.ToString(d.Label) == aws.ToString(r.Label)
+		})
+
+		if i < 0 {
+			dst.MetricDataResults = append(dst.MetricDataResults, r)
+
+			continue
+		}
+
+		d := &dst.MetricDataResults[i]
+
+		d.Timestamps = append(d.Timestamps, r.Timestamps...)
+		d.Values = append(d.Values, r.Values...)
+		d.Messages = append(d.Messages, r.Messages...)
+		d.StatusCode = r.StatusCode
+	}
+}
+
+func (c CloudWatch) checkStatusCodes(results []awstypes.MetricDataResult) error {
+	for _, r := range results {
+		switch r.StatusCode {
+		case awstypes.StatusCodeForbidden, awstypes.StatusCodeInternalError:
+			messages := formatMessages(r.Messages)
+
+			if len(messages) == 0 {
+				messages = formatMessages(c.result.Messages)
+			}
+
+			log.V(3).Trace().
+				Str("package", "cloudwatch").
+				Str("id", aws.ToString(r.Id)).
+				Str("status_code", string(r.StatusCode)).
+				Strs("messages", messages).
+				Msg("query failed")
+
+			return errors.NewCloudWatchError(
+				errors.NewMetricDataError(aws.ToString(r.Id), string(r.StatusCode), strings.Join(messages, "; ")),
+			)
+		}
+	}
 
 	return nil
 }
 
+func formatMessages(messages []awstypes.MessageData) []string {
+	s := make([]string, 0, len(messages))
+
+	for _, m := range messages {
+		if m.Code == nil {
+			s = append(s, aws.ToString(m.Value))
+		} else {
+			s = append(s, fmt.Sprintf("%s: %s", *m.Code, aws.ToString(m.Value)))
+		}
+	}
+
+	return s
+}
+
 func (c CloudWatch) printResult() {
 	r, err := json.Marshal(c.result)
 
@@ -3,6 +3,9 @@ package cloudwatch
 import (
 	"context"
 	goerrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,9 +25,10 @@ func Test_New(t *testing.T) {
 	assert := assert.New(t)
 
 	type args struct {
-		factory    func() (types.Client, error)
+		factory    func(types.Options) (types.Client, error)
 		duration   int
 		queriesStr string
+		stdin      string
 		timeout    int
 	}
 
@@ -39,6 +43,12 @@ func Test_New(t *testing.T) {
 		expected expected
 	}
 
+	queriesFile := filepath.Join(t.TempDir(), "queries.json")
+
+	if err := os.WriteFile(queriesFile, []byte(`[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
 	testCases := []testCase{
 		{
 			name: "success",
@@ -63,33 +73,76 @@ func Test_New(t *testing.T) {
 			},
 		},
 		{
-			name: "client error",
+			name: "illegal queries",
 			args: args{
-				factory: func() (types.Client, error) {
-					return &mock.CloudWatchClient{}, errors.CloudWatchError{}
+				factory:    client.New,
+				duration:   10,
+				queriesStr: `[{"a":true}]`,
+				timeout:    5,
+			},
+			expected: expected{
+				cloudWatch: CloudWatch{
+					duration: 10,
+					queries: []awstypes.MetricDataQuery{
+						{},
+					},
+					timeout: 5,
 				},
+				err: nil,
+			},
+		},
+		{
+			name: "file",
+			args: args{
+				factory:    client.New,
 				duration:   10,
-				queriesStr: `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
+				queriesStr: "@" + queriesFile,
+				timeout:    5,
+			},
+			expected: expected{
+				cloudWatch: CloudWatch{
+					duration: 10,
+					queries: []awstypes.MetricDataQuery{
+						{
+							Id:         aws.String("e1"),
+							Expression: aws.String("TIME_SERIES(1)"),
+						},
+					},
+					timeout: 5,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "unreadable file",
+			args: args{
+				factory:    client.New,
+				duration:   10,
+				queriesStr: "@" + filepath.Join(filepath.Dir(queriesFile), "missing.json"),
 				timeout:    5,
 			},
 			expected: expected{
 				cloudWatch: CloudWatch{},
-				err:        &errors.CloudWatchError{},
+				err:        &errors.ArgumentError{},
 			},
 		},
 		{
-			name: "illegal queries",
+			name: "stdin",
 			args: args{
 				factory:    client.New,
 				duration:   10,
-				queriesStr: `[{"a":true}]`,
+				queriesStr: "-",
+				stdin:      `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`,
 				timeout:    5,
 			},
 			expected: expected{
 				cloudWatch: CloudWatch{
 					duration: 10,
 					queries: []awstypes.MetricDataQuery{
-						{},
+						{
+							Id:         aws.String("e1"),
+							Expression: aws.String("TIME_SERIES(1)"),
+						},
 					},
 					timeout: 5,
 				},
@@ -114,8 +167,9 @@ func Test_New(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			helper.SetCloudWatchClientFactory(t, tc.args.factory)
+			helper.SetInputIO(t, strings.NewReader(tc.args.stdin))
 
-			c, err := New(tc.args.duration, tc.args.queriesStr, tc.args.timeout)
+			c, err := New(tc.args.duration, tc.args.queriesStr, []string{}, Metric{}, types.Options{}, tc.args.timeout)
 
 			if tc.expected.err != nil {
 				assert.ErrorAs(err, tc.expected.err, "is error")
@@ -134,7 +188,7 @@ func Test_GetMetricValues(t *testing.T) {
 	assert := assert.New(t)
 
 	type args struct {
-		factory func() (types.Client, error)
+		factory func(types.Options) (types.Client, error)
 		timeout int
 	}
 
@@ -155,7 +209,7 @@ func Test_GetMetricValues(t *testing.T) {
 		{
 			name: "success",
 			args: args{
-				factory: func() (types.Client, error) {
+				factory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					input := &cloudwatch.GetMetricDataInput{
@@ -202,10 +256,96 @@ func Test_GetMetricValues(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "pagination",
+			args: args{
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					queries := []awstypes.MetricDataQuery{
+						{
+							Id:         aws.String("e1"),
+							Expression: aws.String("TIME_SERIES(1)"),
+						},
+					}
+
+					input1 := &cloudwatch.GetMetricDataInput{
+						StartTime:         aws.Time(time.Date(2022, time.September, 19, 10, 10, 30, 0, time.UTC)),
+						EndTime:           aws.Time(now),
+						MetricDataQueries: queries,
+					}
+
+					output1 := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("e1"),
+								Timestamps: []time.Time{
+									now,
+									time.Date(2022, time.September, 19, 10, 15, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									0.0,
+									0.5,
+								},
+							},
+						},
+						NextToken: aws.String("token1"),
+					}
+
+					input2 := &cloudwatch.GetMetricDataInput{
+						StartTime:         aws.Time(time.Date(2022, time.September, 19, 10, 10, 30, 0, time.UTC)),
+						EndTime:           aws.Time(now),
+						MetricDataQueries: queries,
+						NextToken:         aws.String("token1"),
+					}
+
+					output2 := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id: aws.String("e1"),
+								Timestamps: []time.Time{
+									time.Date(2022, time.September, 19, 10, 10, 30, 0, time.UTC),
+								},
+								Values: []float64{
+									1.0,
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, input1).Return(output1, nil).Once()
+					m.On("GetMetricData", testifymock.Anything, input2).Return(output2, nil).Once()
+
+					return m, nil
+				},
+				timeout: 5,
+			},
+			expected: expected{
+				values: []float64{
+					0.0,
+					0.5,
+					1.0,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "client error",
+			args: args{
+				factory: func(types.Options) (types.Client, error) {
+					return &mock.CloudWatchClient{}, errors.CloudWatchError{}
+				},
+				timeout: 5,
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
 		{
 			name: "API error",
 			args: args{
-				factory: func() (types.Client, error) {
+				factory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					output := &cloudwatch.GetMetricDataOutput{}
@@ -221,10 +361,68 @@ func Test_GetMetricValues(t *testing.T) {
 				err:    &errors.CloudWatchError{},
 			},
 		},
+		{
+			name: "forbidden",
+			args: args{
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id:         aws.String("e1"),
+								StatusCode: awstypes.StatusCodeForbidden,
+								Messages: []awstypes.MessageData{
+									{
+										Code:  aws.String("Forbidden"),
+										Value: aws.String("not authorized to access the metric"),
+									},
+								},
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+				timeout: 5,
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.MetricDataError{},
+			},
+		},
+		{
+			name: "internal error",
+			args: args{
+				factory: func(types.Options) (types.Client, error) {
+					m := &mock.CloudWatchClient{}
+
+					output := &cloudwatch.GetMetricDataOutput{
+						MetricDataResults: []awstypes.MetricDataResult{
+							{
+								Id:         aws.String("e1"),
+								StatusCode: awstypes.StatusCodeInternalError,
+							},
+						},
+					}
+
+					m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+				timeout: 5,
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
 		{
 			name: "timeout",
 			args: args{
-				factory: func() (types.Client, error) {
+				factory: func(types.Options) (types.Client, error) {
 					m := &mock.CloudWatchClient{}
 
 					ctx, cancel := context.WithDeadline(
@@ -253,7 +451,7 @@ func Test_GetMetricValues(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			helper.SetCloudWatchClientFactory(t, tc.args.factory)
 
-			c, err := New(10, `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`, tc.args.timeout)
+			c, err := New(10, `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`, []string{}, Metric{}, types.Options{}, tc.args.timeout)
 
 			if err != nil {
 				t.Error(err)
@@ -283,7 +481,7 @@ func Test_LatestValue(t *testing.T) {
 
 	type testCase struct {
 		name     string
-		args     func() (types.Client, error)
+		args     func(types.Options) (types.Client, error)
 		expected expected
 	}
 
@@ -292,7 +490,7 @@ func Test_LatestValue(t *testing.T) {
 	testCases := []testCase{
 		{
 			name: "exists",
-			args: func() (types.Client, error) {
+			args: func(types.Options) (types.Client, error) {
 				m := &mock.CloudWatchClient{}
 
 				output := &cloudwatch.GetMetricDataOutput{
@@ -325,7 +523,7 @@ func Test_LatestValue(t *testing.T) {
 		},
 		{
 			name: "empty",
-			args: func() (types.Client, error) {
+			args: func(types.Options) (types.Client, error) {
 				m := &mock.CloudWatchClient{}
 
 				output := &cloudwatch.GetMetricDataOutput{
@@ -350,7 +548,7 @@ func Test_LatestValue(t *testing.T) {
 		},
 		{
 			name: "label",
-			args: func() (types.Client, error) {
+			args: func(types.Options) (types.Client, error) {
 				m := &mock.CloudWatchClient{}
 
 				output := &cloudwatch.GetMetricDataOutput{
@@ -388,7 +586,7 @@ func Test_LatestValue(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			helper.SetCloudWatchClientFactory(t, tc.args)
 
-			c, err := New(10, `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`, 5)
+			c, err := New(10, `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`, []string{}, Metric{}, types.Options{}, 5)
 
 			if err != nil {
 				t.Error(err)
@@ -408,3 +606,446 @@ func Test_LatestValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetMetricSeries(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		series []Series
+		err    error
+	}
+
+	type testCase struct {
+		name     string
+		args     func(types.Options) (types.Client, error)
+		expected expected
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "multiple series",
+			args: func(types.Options) (types.Client, error) {
+				m := &mock.CloudWatchClient{}
+
+				output := &cloudwatch.GetMetricDataOutput{
+					MetricDataResults: []awstypes.MetricDataResult{
+						{
+							Id:    aws.String("e1"),
+							Label: aws.String("i-1"),
+							Timestamps: []time.Time{
+								now,
+							},
+							Values: []float64{
+								0.5,
+							},
+						},
+						{
+							Id:    aws.String("e1"),
+							Label: aws.String("i-2"),
+							Timestamps: []time.Time{
+								now,
+							},
+							Values: []float64{
+								1.5,
+							},
+						},
+					},
+				}
+
+				m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+				return m, nil
+			},
+			expected: expected{
+				series: []Series{
+					{
+						Id:         "e1",
+						Label:      "i-1",
+						Timestamps: []time.Time{now},
+						Values:     []float64{0.5},
+					},
+					{
+						Id:         "e1",
+						Label:      "i-2",
+						Timestamps: []time.Time{now},
+						Values:     []float64{1.5},
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "pagination",
+			args: func(types.Options) (types.Client, error) {
+				m := &mock.CloudWatchClient{}
+
+				output1 := &cloudwatch.GetMetricDataOutput{
+					MetricDataResults: []awstypes.MetricDataResult{
+						{
+							Id:    aws.String("e1"),
+							Label: aws.String("i-1"),
+							Timestamps: []time.Time{
+								now,
+							},
+							Values: []float64{
+								0.5,
+							},
+						},
+					},
+					NextToken: aws.String("token1"),
+				}
+
+				output2 := &cloudwatch.GetMetricDataOutput{
+					MetricDataResults: []awstypes.MetricDataResult{
+						{
+							Id:    aws.String("e1"),
+							Label: aws.String("i-2"),
+							Timestamps: []time.Time{
+								now,
+							},
+							Values: []float64{
+								1.5,
+							},
+						},
+						{
+							Id:    aws.String("e1"),
+							Label: aws.String("i-1"),
+							Timestamps: []time.Time{
+								time.Date(2022, time.September, 19, 10, 15, 30, 0, time.UTC),
+							},
+							Values: []float64{
+								1.0,
+							},
+						},
+					},
+				}
+
+				m.On("GetMetricData", testifymock.Anything, testifymock.MatchedBy(func(input *cloudwatch.GetMetricDataInput) bool {
+					return input.NextToken == nil
+				})).Return(output1, nil).Once()
+
+				m.On("GetMetricData", testifymock.Anything, testifymock.MatchedBy(func(input *cloudwatch.GetMetricDataInput) bool {
+					return aws.ToString(input.NextToken) == "token1"
+				})).Return(output2, nil).Once()
+
+				return m, nil
+			},
+			expected: expected{
+				series: []Series{
+					{
+						Id:     "e1",
+						Label:  "i-1",
+						Period: 300,
+						Timestamps: []time.Time{
+							now,
+							time.Date(2022, time.September, 19, 10, 15, 30, 0, time.UTC),
+						},
+						Values: []float64{0.5, 1.0},
+					},
+					{
+						Id:         "e1",
+						Label:      "i-2",
+						Timestamps: []time.Time{now},
+						Values:     []float64{1.5},
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "no results",
+			args: func(types.Options) (types.Client, error) {
+				m := &mock.CloudWatchClient{}
+
+				output := &cloudwatch.GetMetricDataOutput{
+					MetricDataResults: []awstypes.MetricDataResult{},
+				}
+
+				m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+				return m, nil
+			},
+			expected: expected{
+				series: []Series{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "API error",
+			args: func(types.Options) (types.Client, error) {
+				m := &mock.CloudWatchClient{}
+
+				output := &cloudwatch.GetMetricDataOutput{}
+
+				m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, goerrors.New(""))
+
+				return m, nil
+			},
+			expected: expected{
+				series: []Series{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetCloudWatchClientFactory(t, tc.args)
+
+			c, err := New(10, `[{"Id":"e1","Expression":"SEARCH('{AWS/EC2,InstanceId} CPUUtilization', 'Average')"}]`, []string{}, Metric{}, types.Options{}, 5)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			series, err := c.GetMetricSeries(now)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.series, series, "series")
+			}
+		})
+	}
+}
+
+func Test_GetMetricSeries_unit(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	helper.SetCloudWatchClientFactory(t, func(types.Options) (types.Client, error) {
+		m := &mock.CloudWatchClient{}
+
+		output := &cloudwatch.GetMetricDataOutput{
+			MetricDataResults: []awstypes.MetricDataResult{
+				{
+					Id:         aws.String("m1"),
+					Label:      aws.String("CPUUtilization"),
+					Timestamps: []time.Time{now},
+					Values:     []float64{12.5},
+				},
+				{
+					Id:         aws.String("e1"),
+					Label:      aws.String("e1"),
+					Timestamps: []time.Time{now},
+					Values:     []float64{25},
+				},
+			},
+		}
+
+		m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+		return m, nil
+	})
+
+	c, err := New(
+		10,
+		`[{"Id":"m1","MetricStat":{"Metric":{"Namespace":"AWS/EC2","MetricName":"CPUUtilization"},"Period":300,"Stat":"Average","Unit":"Percent"}},{"Id":"e1","Expression":"m1*2"}]`,
+		[]string{}, Metric{}, types.Options{}, 5,
+	)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	series, err := c.GetMetricSeries(now)
+
+	assert.Nil(err, "is not error")
+
+	assert.Equal("Percent", series[0].Unit, "metric stat")
+	assert.Equal("", series[1].Unit, "expression")
+}
+
+func Test_GetMetricSeries_cache(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		ttl       int
+		durations []int
+		elapsed   time.Duration
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected int
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	testCases := []testCase{
+		{
+			name: "identical requests",
+			args: args{
+				ttl:       60,
+				durations: []int{10, 10, 10},
+				elapsed:   20 * time.Second,
+			},
+			expected: 1,
+		},
+		{
+			name: "different time window",
+			args: args{
+				ttl:       60,
+				durations: []int{10, 20},
+				elapsed:   30 * time.Second,
+			},
+			expected: 2,
+		},
+		{
+			name: "expired",
+			args: args{
+				ttl:       60,
+				durations: []int{10, 10},
+				elapsed:   60 * time.Second,
+			},
+			expected: 2,
+		},
+		{
+			name: "disabled",
+			args: args{
+				ttl:       0,
+				durations: []int{10, 10},
+				elapsed:   30 * time.Second,
+			},
+			expected: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			m := &mock.CloudWatchClient{}
+
+			output := &cloudwatch.GetMetricDataOutput{
+				MetricDataResults: []awstypes.MetricDataResult{
+					{
+						Id:         aws.String("e1"),
+						Label:      aws.String("i-1"),
+						Timestamps: []time.Time{now},
+						Values:     []float64{0.5},
+					},
+				},
+			}
+
+			m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+			clients := 0
+
+			helper.SetCloudWatchClientFactory(t, func(types.Options) (types.Client, error) {
+				clients++
+
+				return m, nil
+			})
+
+			options := types.Options{
+				CacheDir: t.TempDir(),
+				CacheTTL: tc.args.ttl,
+			}
+
+			for i, duration := range tc.args.durations {
+				c, err := New(duration, `[{"Id":"e1","Expression":"SEARCH('{AWS/EC2,InstanceId} CPUUtilization', 'Average')"}]`, []string{}, Metric{}, options, 5)
+
+				if err != nil {
+					t.Error(err)
+				}
+
+				series, err := c.GetMetricSeries(now.Add(time.Duration(i) * tc.args.elapsed))
+
+				assert.Nil(err, "is not error")
+
+				assert.Equal([]float64{0.5}, series[0].Values, "values")
+				assert.True(now.Equal(series[0].Timestamps[0]), "timestamp")
+			}
+
+			m.AssertNumberOfCalls(t, "GetMetricData", tc.expected)
+
+			assert.Equal(tc.expected, clients, "clients")
+		})
+	}
+}
+
+func Test_Warnings(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     *cloudwatch.GetMetricDataOutput
+		expected []string
+	}
+
+	testCases := []testCase{
+		{
+			name: "complete",
+			args: &cloudwatch.GetMetricDataOutput{
+				MetricDataResults: []awstypes.MetricDataResult{
+					{
+						Id:         aws.String("e1"),
+						StatusCode: awstypes.StatusCodeComplete,
+					},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name: "partial data",
+			args: &cloudwatch.GetMetricDataOutput{
+				Messages: []awstypes.MessageData{
+					{
+						Code:  aws.String("MaxMetricsExceeded"),
+						Value: aws.String("too many metrics"),
+					},
+				},
+				MetricDataResults: []awstypes.MetricDataResult{
+					{
+						Id:         aws.String("e1"),
+						Label:      aws.String("a"),
+						StatusCode: awstypes.StatusCodePartialData,
+						Messages: []awstypes.MessageData{
+							{
+								Value: aws.String("ArithmeticError"),
+							},
+						},
+					},
+				},
+			},
+			expected: []string{
+				"MaxMetricsExceeded: too many metrics",
+				"partial data returned for a",
+				"a: ArithmeticError",
+			},
+		},
+	}
+
+	now := time.Date(2022, time.September, 19, 10, 20, 30, 0, time.UTC)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetCloudWatchClientFactory(t, func(types.Options) (types.Client, error) {
+				m := &mock.CloudWatchClient{}
+
+				m.On("GetMetricData", testifymock.Anything, testifymock.Anything).Return(tc.args, nil)
+
+				return m, nil
+			})
+
+			c, err := New(10, `[{"Id":"e1","Expression":"TIME_SERIES(1)"}]`, []string{}, Metric{}, types.Options{}, 5)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			if _, err := c.GetMetricValues(now); err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(tc.expected, c.Warnings(), "warnings")
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package cloudwatch
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/container"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type Insights struct {
+	client       types.LogsClient
+	duration     int
+	logGroups    []string
+	query        string
+	field        string
+	emptyValue   *float64
+	timeout      int
+	pollInterval time.Duration
+	result       *cloudwatchlogs.GetQueryResultsOutput
+}
+
+func NewInsights(
+	duration int, logGroups []string, queryStr string, field string, emptyValue string,
+	options types.Options, timeout int,
+) (Insights, error) {
+	client, err := container.GetLogsClient(options)
+
+	if err != nil {
+		return Insights{}, err
+	}
+
+	query, err := readQueries(queryStr, "logs-query")
+
+	if err != nil {
+		return Insights{}, err
+	}
+
+	var empty *float64
+
+	if emptyValue != "" {
+		v, err := strconv.ParseFloat(emptyValue, 64)
+
+		if err != nil {
+			return Insights{}, errors.NewArgumentErrorWithError(err, "empty-value", emptyValue)
+		}
+
+		empty = &v
+	}
+
+	return Insights{
+		client:       client,
+		duration:     duration,
+		logGroups:    logGroups,
+		query:        string(query),
+		field:        field,
+		emptyValue:   empty,
+		timeout:      timeout,
+		pollInterval: time.Second,
+	}, nil
+}
+
+func (i *Insights) GetValues(now time.Time) ([]float64, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Msg("running Logs Insights query")
+
+	if err := i.runQuery(now); err != nil {
+		return []float64{}, err
+	}
+
+	i.printResult()
+
+	return i.values()
+}
+
+func (i Insights) Field() string {
+	return i.field
+}
+
+func (i *Insights) runQuery(now time.Time) error {
+	startTime := now.Add(-1 * time.Duration(i.duration) * time.Minute)
+
+	ctx, cancel := context.WithDeadline(
+		context.Background(),
+		now.Add(time.Duration(i.timeout)*time.Second),
+	)
+
+	defer cancel()
+
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Strs("log_groups", i.logGroups).
+		Time("start_time", startTime).
+		Time("end_time", now).
+		Int("timeout", i.timeout).
+		Msg("API parameters")
+
+	started, err := i.client.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: i.logGroups,
+		QueryString:   aws.String(i.query),
+		StartTime:     aws.Int64(startTime.Unix()),
+		EndTime:       aws.Int64(now.Unix()),
+	})
+
+	if err != nil {
+		return errors.NewCloudWatchError(err)
+	}
+
+	queryID := aws.ToString(started.QueryId)
+
+	for attempt := 1; ; attempt++ {
+		output, err := i.client.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: started.QueryId,
+		})
+
+		if err != nil {
+			i.stopQuery(queryID)
+
+			return errors.NewCloudWatchError(err)
+		}
+
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Str("query_id", queryID).
+			Int("attempt", attempt).
+			Str("status", string(output.Status)).
+			Msg("query status retrieved")
+
+		switch output.Status {
+		case logstypes.QueryStatusComplete:
+			i.result = output
+
+			return nil
+		case logstypes.QueryStatusScheduled, logstypes.QueryStatusRunning:
+		default:
+			return errors.NewCloudWatchError(fmt.Errorf("query %s ended with status %s", queryID, output.Status))
+		}
+
+		select {
+		case <-ctx.Done():
+			i.stopQuery(queryID)
+
+			return errors.NewCloudWatchError(fmt.Errorf("query %s did not complete: %w", queryID, ctx.Err()))
+		case <-time.After(i.pollInterval):
+		}
+	}
+}
+
+func (i Insights) stopQuery(queryID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+	defer cancel()
+
+	if _, err := i.client.StopQuery(ctx, &cloudwatchlogs.StopQueryInput{QueryId: aws.String(queryID)}); err != nil {
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Str("query_id", queryID).
+			Err(err).
+			Msg("failed to stop query")
+	}
+}
+
+func (i *Insights) values() ([]float64, error) {
+	values := make([]float64, 0, len(i.result.Results))
+
+	for _, row := range i.result.Results {
+		field, value, ok := i.lookup(row)
+
+		if !ok {
+			return []float64{}, errors.NewCloudWatchError(fmt.Errorf("field %q not found in query results", i.field))
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			return []float64{}, errors.NewCloudWatchError(fmt.Errorf("field %q is not numeric: %q", field, value))
+		}
+
+		i.field = field
+
+		values = append(values, v)
+	}
+
+	if len(values) == 0 && i.emptyValue != nil {
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Float64("empty_value", *i.emptyValue).
+			Msg("no query results returned, using the empty value")
+
+		return []float64{*i.emptyValue}, nil
+	}
+
+	if len(values) == 0 {
+		return []float64{}, errors.NewCloudWatchError(goerrors.New("no query results returned"))
+	}
+
+	return values, nil
+}
+
+func (i Insights) lookup(row []logstypes.ResultField) (field string, value string, ok bool) {
+	for _, f := range row {
+		name := aws.ToString(f.Field)
+
+		if (i.field == "" && !strings.HasPrefix(name, "@")) || name == i.field {
+			return name, aws.ToString(f.Value), true
+		}
+	}
+
+	return "", "", false
+}
+
+func (i Insights) printResult() {
+	r, err := json.Marshal(i.result)
+
+	if err != nil {
+		log.V(2).Error().
+			Err(err).
+			Msg("failed to marshal GetQueryResultsOutput")
+	} else {
+		log.V(2).Debug().
+			RawJSON("GetQueryResultsOutput", r).
+			Msg("API call succeeds")
+	}
+}
@@ -0,0 +1,474 @@
+package cloudwatch
+
+import (
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func Test_NewInsights(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		factory    func(types.Options) (types.LogsClient, error)
+		queryStr   string
+		emptyValue string
+	}
+
+	type expected struct {
+		query      string
+		emptyValue *float64
+		err        error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	queryFile := filepath.Join(t.TempDir(), "query.txt")
+
+	if err := os.WriteFile(queryFile, []byte("stats count(*) as errors"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	factory := func(types.Options) (types.LogsClient, error) {
+		return &mock.LogsClient{}, nil
+	}
+
+	testCases := []testCase{
+		{
+			name: "inline",
+			args: args{
+				factory:  factory,
+				queryStr: "stats count(*) as errors",
+			},
+			expected: expected{
+				query: "stats count(*) as errors",
+				err:   nil,
+			},
+		},
+		{
+			name: "file",
+			args: args{
+				factory:  factory,
+				queryStr: "@" + queryFile,
+			},
+			expected: expected{
+				query: "stats count(*) as errors",
+				err:   nil,
+			},
+		},
+		{
+			name: "unreadable file",
+			args: args{
+				factory:  factory,
+				queryStr: "@" + filepath.Join(t.TempDir(), "UNKNOWN"),
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "empty value",
+			args: args{
+				factory:    factory,
+				queryStr:   "stats count(*) as errors",
+				emptyValue: "0",
+			},
+			expected: expected{
+				query:      "stats count(*) as errors",
+				emptyValue: aws.Float64(0),
+				err:        nil,
+			},
+		},
+		{
+			name: "invalid empty value",
+			args: args{
+				factory:    factory,
+				queryStr:   "stats count(*) as errors",
+				emptyValue: "INVALID",
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "client error",
+			args: args{
+				factory: func(types.Options) (types.LogsClient, error) {
+					return &mock.LogsClient{}, errors.CloudWatchError{}
+				},
+				queryStr: "stats count(*) as errors",
+			},
+			expected: expected{
+				err: &errors.CloudWatchError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+			helper.SetLogsClientFactory(t, tc.args.factory)
+
+			i, err := NewInsights(15, []string{"/app/web"}, tc.args.queryStr, "errors", tc.args.emptyValue, types.Options{}, 5)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.query, i.query, "query")
+				assert.Equal([]string{"/app/web"}, i.logGroups, "log groups")
+				assert.Equal(tc.expected.emptyValue, i.emptyValue, "empty value")
+			}
+		})
+	}
+}
+
+func Test_Insights_GetValues(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		field      string
+		emptyValue string
+		timeout    int
+		factory    func(types.Options) (types.LogsClient, error)
+	}
+
+	type expected struct {
+		values []float64
+		field  string
+		err    error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	now := time.Now().Truncate(time.Second)
+
+	row := func(fields ...string) []logstypes.ResultField {
+		r := []logstypes.ResultField{}
+
+		for i := 0; i+1 < len(fields); i += 2 {
+			r = append(r, logstypes.ResultField{
+				Field: aws.String(fields[i]),
+				Value: aws.String(fields[i+1]),
+			})
+		}
+
+		return r
+	}
+
+	started := &cloudwatchlogs.StartQueryOutput{
+		QueryId: aws.String("q1"),
+	}
+
+	testCases := []testCase{
+		{
+			name: "complete",
+			args: args{
+				field:   "errors",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					input := &cloudwatchlogs.StartQueryInput{
+						LogGroupNames: []string{"/app/web"},
+						QueryString:   aws.String("stats count(*) as errors by bin(5m)"),
+						StartTime:     aws.Int64(now.Add(-15 * time.Minute).Unix()),
+						EndTime:       aws.Int64(now.Unix()),
+					}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							row("bin(5m)", "2022-09-19 10:15:00.000", "errors", "3"),
+							row("bin(5m)", "2022-09-19 10:10:00.000", "errors", "1"),
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, input).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{3, 1},
+				field:  "errors",
+				err:    nil,
+			},
+		},
+		{
+			name: "polling",
+			args: args{
+				field:   "errors",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					running := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusRunning,
+					}
+
+					complete := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							row("errors", "7"),
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(running, nil).Twice()
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(complete, nil).Once()
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{7},
+				field:  "errors",
+				err:    nil,
+			},
+		},
+		{
+			name: "first field",
+			args: args{
+				field:   "",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							row("@ptr", "abc", "count(*)", "42"),
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{42},
+				field:  "count(*)",
+				err:    nil,
+			},
+		},
+		{
+			name: "timeout",
+			args: args{
+				field:   "errors",
+				timeout: 0,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					running := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusRunning,
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(running, nil)
+					m.On("StopQuery", testifymock.Anything, &cloudwatchlogs.StopQueryInput{QueryId: aws.String("q1")}).Return(&cloudwatchlogs.StopQueryOutput{}, nil).Once()
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "failed",
+			args: args{
+				field:   "errors",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					failed := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusFailed,
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(failed, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "no results",
+			args: args{
+				field:   "errors",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "no results with empty value",
+			args: args{
+				field:      "errors",
+				emptyValue: "0",
+				timeout:    5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{0},
+				field:  "errors",
+				err:    nil,
+			},
+		},
+		{
+			name: "field not found",
+			args: args{
+				field:   "UNKNOWN",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							row("errors", "3"),
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "non-numeric field",
+			args: args{
+				field:   "errors",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					output := &cloudwatchlogs.GetQueryResultsOutput{
+						Status: logstypes.QueryStatusComplete,
+						Results: [][]logstypes.ResultField{
+							row("errors", "many"),
+						},
+					}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(started, nil)
+					m.On("GetQueryResults", testifymock.Anything, testifymock.Anything).Return(output, nil)
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+		{
+			name: "start query error",
+			args: args{
+				field:   "errors",
+				timeout: 5,
+				factory: func(types.Options) (types.LogsClient, error) {
+					m := &mock.LogsClient{}
+
+					m.On("StartQuery", testifymock.Anything, testifymock.Anything).Return(&cloudwatchlogs.StartQueryOutput{}, goerrors.New(""))
+
+					return m, nil
+				},
+			},
+			expected: expected{
+				values: []float64{},
+				err:    &errors.CloudWatchError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+			helper.SetLogsClientFactory(t, tc.args.factory)
+
+			i, err := NewInsights(15, []string{"/app/web"}, "stats count(*) as errors by bin(5m)", tc.args.field, tc.args.emptyValue, types.Options{}, tc.args.timeout)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			i.pollInterval = time.Millisecond
+
+			values, err := i.GetValues(now)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.field, i.Field(), "field")
+			}
+
+			assert.Equal(tc.expected.values, values, "values")
+
+			i.client.(*mock.LogsClient).AssertExpectations(t)
+		})
+	}
+}
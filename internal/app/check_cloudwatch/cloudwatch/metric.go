@@ -0,0 +1,96 @@
+package cloudwatch
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+type Metric struct {
+	Namespace  string
+	MetricName string
+	Dimensions []string
+	Stat       string
+	Period     int
+	SQL        string
+}
+
+func buildQueries(metric Metric) ([]awstypes.MetricDataQuery, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Msg("building API queries from metric")
+
+	dimensions := make([]awstypes.Dimension, 0, len(metric.Dimensions))
+
+	for _, d := range metric.Dimensions {
+		name, value, found := strings.Cut(d, "=")
+
+		if !found || name == "" {
+			return nil, errors.NewArgumentErrorWithMessage("dimension is specified in the format 'NAME=VALUE'", "dimension", d)
+		}
+
+		dimensions = append(dimensions, awstypes.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(value),
+		})
+	}
+
+	q := []awstypes.MetricDataQuery{
+		{
+			Id:    aws.String("m1"),
+			Label: aws.String(metric.MetricName),
+			MetricStat: &awstypes.MetricStat{
+				Metric: &awstypes.Metric{
+					Namespace:  aws.String(metric.Namespace),
+					MetricName: aws.String(metric.MetricName),
+					Dimensions: dimensions,
+				},
+				Period: aws.Int32(int32(metric.Period)),
+				Stat:   aws.String(metric.Stat),
+			},
+		},
+	}
+
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Interface("queries", q).
+		Send()
+
+	return q, nil
+}
+
+func buildSQLQueries(metric Metric) ([]awstypes.MetricDataQuery, error) {
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Msg("building API queries from Metrics Insights query")
+
+	b, err := readQueries(metric.SQL, "sql")
+
+	if err != nil {
+		return nil, err
+	}
+
+	sql := strings.TrimSpace(string(b))
+
+	if sql == "" {
+		return nil, errors.NewArgumentErrorWithMessage("Metrics Insights query is empty", "sql", metric.SQL)
+	}
+
+	q := []awstypes.MetricDataQuery{
+		{
+			Id:         aws.String("q1"),
+			Expression: aws.String(sql),
+			Period:     aws.Int32(int32(metric.Period)),
+		},
+	}
+
+	log.V(3).Trace().
+		Str("package", "cloudwatch").
+		Interface("queries", q).
+		Send()
+
+	return q, nil
+}
@@ -0,0 +1,193 @@
+package cloudwatch
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_buildQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		queries []awstypes.MetricDataQuery
+		err     error
+	}
+
+	type testCase struct {
+		name     string
+		args     Metric
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "with dimensions",
+			args: Metric{
+				Namespace:  "AWS/EC2",
+				MetricName: "CPUUtilization",
+				Dimensions: []string{
+					"InstanceId=i-123",
+				},
+				Stat:   "Average",
+				Period: 300,
+			},
+			expected: expected{
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:    aws.String("m1"),
+						Label: aws.String("CPUUtilization"),
+						MetricStat: &awstypes.MetricStat{
+							Metric: &awstypes.Metric{
+								Namespace:  aws.String("AWS/EC2"),
+								MetricName: aws.String("CPUUtilization"),
+								Dimensions: []awstypes.Dimension{
+									{
+										Name:  aws.String("InstanceId"),
+										Value: aws.String("i-123"),
+									},
+								},
+							},
+							Period: aws.Int32(300),
+							Stat:   aws.String("Average"),
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "without dimensions",
+			args: Metric{
+				Namespace:  "AWS/S3",
+				MetricName: "BucketSizeBytes",
+				Dimensions: []string{},
+				Stat:       "Maximum",
+				Period:     86400,
+			},
+			expected: expected{
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:    aws.String("m1"),
+						Label: aws.String("BucketSizeBytes"),
+						MetricStat: &awstypes.MetricStat{
+							Metric: &awstypes.Metric{
+								Namespace:  aws.String("AWS/S3"),
+								MetricName: aws.String("BucketSizeBytes"),
+								Dimensions: []awstypes.Dimension{},
+							},
+							Period: aws.Int32(86400),
+							Stat:   aws.String("Maximum"),
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "invalid dimension",
+			args: Metric{
+				Namespace:  "AWS/EC2",
+				MetricName: "CPUUtilization",
+				Dimensions: []string{
+					"InstanceId",
+				},
+				Stat:   "Average",
+				Period: 300,
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queries, err := buildQueries(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.queries, queries, "queries")
+			}
+		})
+	}
+}
+
+func Test_buildSQLQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		queries []awstypes.MetricDataQuery
+		err     error
+	}
+
+	type testCase struct {
+		name     string
+		args     Metric
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "query",
+			args: Metric{
+				SQL:    ` SELECT AVG(Errors) FROM SCHEMA("AWS/Lambda", FunctionName) GROUP BY FunctionName` + "\n",
+				Period: 60,
+			},
+			expected: expected{
+				queries: []awstypes.MetricDataQuery{
+					{
+						Id:         aws.String("q1"),
+						Expression: aws.String(`SELECT AVG(Errors) FROM SCHEMA("AWS/Lambda", FunctionName) GROUP BY FunctionName`),
+						Period:     aws.Int32(60),
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "empty query",
+			args: Metric{
+				SQL:    " ",
+				Period: 60,
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "unreadable file",
+			args: Metric{
+				SQL:    "@" + filepath.Join(t.TempDir(), "UNKNOWN"),
+				Period: 60,
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.SetLogOutputDiscard(t)
+
+			queries, err := buildSQLQueries(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.queries, queries, "queries")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cloudwatch
+
+import (
+	"time"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+type Series struct {
+	Id         string
+	Label      string
+	Period     int32
+	Unit       string
+	Timestamps []time.Time
+	Values     []float64
+}
+
+func newSeries(r awstypes.MetricDataResult) Series {
+	s := Series{
+		Timestamps: r.Timestamps,
+		Values:     r.Values,
+	}
+
+	if r.Id != nil {
+		s.Id = *r.Id
+	}
+
+	if r.Label != nil {
+		s.Label = *r.Label
+	}
+
+	return s
+}
+
+func (s Series) Name() string {
+	if s.Label == "" {
+		return s.Id
+	}
+
+	return s.Label
+}
+
+func (s Series) Latest() (value float64, timestamp time.Time) {
+	if len(s.Values) != 0 {
+		value = s.Values[0]
+	}
+
+	if len(s.Timestamps) != 0 {
+		timestamp = s.Timestamps[0]
+	}
+
+	return
+}
+
+func inferPeriod(timestamps []time.Time) int32 {
+	var period time.Duration
+
+	for i := 1; i < len(timestamps); i++ {
+		d := timestamps[i-1].Sub(timestamps[i]).Abs()
+
+		if 0 < d && (period == 0 || d < period) {
+			period = d
+		}
+	}
+
+	return int32(period / time.Second)
+}
@@ -0,0 +1,51 @@
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_inferPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	type testCase struct {
+		name     string
+		args     []time.Time
+		expected int32
+	}
+
+	testCases := []testCase{
+		{
+			name: "descending",
+			args: []time.Time{
+				time.Date(2022, time.September, 19, 10, 20, 0, 0, time.UTC),
+				time.Date(2022, time.September, 19, 10, 10, 0, 0, time.UTC),
+				time.Date(2022, time.September, 19, 10, 5, 0, 0, time.UTC),
+			},
+			expected: 300,
+		},
+		{
+			name: "ascending",
+			args: []time.Time{
+				time.Date(2022, time.September, 19, 10, 5, 0, 0, time.UTC),
+				time.Date(2022, time.September, 19, 10, 6, 0, 0, time.UTC),
+			},
+			expected: 60,
+		},
+		{
+			name: "single datapoint",
+			args: []time.Time{
+				time.Date(2022, time.September, 19, 10, 5, 0, 0, time.UTC),
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, inferPeriod(tc.args), "period")
+		})
+	}
+}
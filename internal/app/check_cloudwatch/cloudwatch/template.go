@@ -0,0 +1,58 @@
+package cloudwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
+)
+
+var varKeyPattern = regexp.MustCompile(`\A[A-Za-z_][A-Za-z0-9_]*\z`)
+
+func parseVars(vars []string) (map[string]string, error) {
+	m := make(map[string]string, len(vars))
+
+	for _, v := range vars {
+		key, value, found := strings.Cut(v, "=")
+
+		if !found || !varKeyPattern.MatchString(key) {
+			return nil, errors.NewArgumentErrorWithMessage("variable is specified in the format 'KEY=VALUE'", "var", v)
+		}
+
+		log.V(3).Trace().
+			Str("package", "cloudwatch").
+			Str("key", key).
+			Str("value", value).
+			Msg("query variable")
+
+		m[key] = escapeJSON(value)
+	}
+
+	return m, nil
+}
+
+func renderQueries(queries []byte, vars map[string]string) ([]byte, error) {
+	tmpl, err := template.New("queries").Option("missingkey=error").Parse(string(queries))
+
+	if err != nil {
+		return nil, errors.NewArgumentErrorWithError(err, "queries", string(queries))
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := tmpl.Execute(buf, vars); err != nil {
+		return nil, errors.NewArgumentErrorWithError(err, "queries", string(queries))
+	}
+
+	return buf.Bytes(), nil
+}
+
+func escapeJSON(s string) string {
+	b, _ := json.Marshal(s)
+
+	return string(b[1 : len(b)-1])
+}
@@ -0,0 +1,158 @@
+package cloudwatch
+
+import (
+	"testing"
+
+	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseVars(t *testing.T) {
+	assert := assert.New(t)
+
+	type expected struct {
+		vars map[string]string
+		err  error
+	}
+
+	type testCase struct {
+		name     string
+		args     []string
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "valid",
+			args: []string{
+				"VolumeId=vol-123",
+				"Empty=",
+				"Quoted=a\"b=c",
+			},
+			expected: expected{
+				vars: map[string]string{
+					"VolumeId": "vol-123",
+					"Empty":    "",
+					"Quoted":   `a\"b=c`,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "no separator",
+			args: []string{
+				"VolumeId",
+			},
+			expected: expected{
+				vars: nil,
+				err:  &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "invalid key",
+			args: []string{
+				"Volume-Id=vol-123",
+			},
+			expected: expected{
+				vars: nil,
+				err:  &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars, err := parseVars(tc.args)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.vars, vars, "vars")
+			}
+		})
+	}
+}
+
+func Test_renderQueries(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		queries string
+		vars    map[string]string
+	}
+
+	type expected struct {
+		queries string
+		err     error
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected expected
+	}
+
+	testCases := []testCase{
+		{
+			name: "substituted",
+			args: args{
+				queries: `[{"Id":"m1","MetricStat":{"Metric":{"Dimensions":[{"Name":"VolumeId","Value":"{{.VolumeId}}"}]},"Period":{{.Period}}}}]`,
+				vars: map[string]string{
+					"VolumeId": "vol-123",
+					"Period":   "300",
+				},
+			},
+			expected: expected{
+				queries: `[{"Id":"m1","MetricStat":{"Metric":{"Dimensions":[{"Name":"VolumeId","Value":"vol-123"}]},"Period":300}}]`,
+				err:     nil,
+			},
+		},
+		{
+			name: "no placeholders",
+			args: args{
+				queries: `[{"Id":"e1","Expression":"SEARCH('{AWS/EC2,InstanceId} CPUUtilization', 'Average')"}]`,
+				vars:    map[string]string{},
+			},
+			expected: expected{
+				queries: `[{"Id":"e1","Expression":"SEARCH('{AWS/EC2,InstanceId} CPUUtilization', 'Average')"}]`,
+				err:     nil,
+			},
+		},
+		{
+			name: "unresolved placeholder",
+			args: args{
+				queries: `[{"Id":"m1","Label":"{{.VolumeId}}"}]`,
+				vars:    map[string]string{},
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+		{
+			name: "invalid template",
+			args: args{
+				queries: `[{"Id":"m1","Label":"{{.VolumeId"}]`,
+				vars:    map[string]string{},
+			},
+			expected: expected{
+				err: &errors.ArgumentError{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queries, err := renderQueries([]byte(tc.args.queries), tc.args.vars)
+
+			if tc.expected.err != nil {
+				assert.ErrorAs(err, tc.expected.err, "is error")
+			} else {
+				assert.Nil(err, "is not error")
+
+				assert.Equal(tc.expected.queries, string(queries), "queries")
+			}
+		})
+	}
+}
@@ -7,18 +7,30 @@ import (
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/cloudwatch/client/types"
 )
 
-var CloudWatchClientFactory func() (types.Client, error)
+var CloudWatchClientFactory func(types.Options) (types.Client, error)
+var LogsClientFactory func(types.Options) (types.LogsClient, error)
 var LoggerIO io.Writer = os.Stdout
+var InputIO io.Reader = os.Stdin
 
 func Reset() {
 	CloudWatchClientFactory = nil
+	LogsClientFactory = nil
 	LoggerIO = os.Stdout
+	InputIO = os.Stdin
 }
 
-func GetCloudWatchClient() (types.Client, error) {
-	return CloudWatchClientFactory()
+func GetCloudWatchClient(options types.Options) (types.Client, error) {
+	return CloudWatchClientFactory(options)
+}
+
+func GetLogsClient(options types.Options) (types.LogsClient, error) {
+	return LogsClientFactory(options)
 }
 
 func GetLoggerIO() io.Writer {
 	return LoggerIO
 }
+
+func GetInputIO() io.Reader {
+	return InputIO
+}
@@ -15,6 +15,17 @@ type CloudWatchError struct {
 	err error
 }
 
+type AssumeRoleError struct {
+	err     error
+	roleARN string
+}
+
+type MetricDataError struct {
+	id         string
+	statusCode string
+	message    string
+}
+
 func NewArgumentErrorWithError(err error, key string, value string) ArgumentError {
 	return ArgumentError{
 		err:   err,
@@ -33,6 +44,21 @@ func NewCloudWatchError(err error) CloudWatchError {
 	}
 }
 
+func NewAssumeRoleError(err error, roleARN string) AssumeRoleError {
+	return AssumeRoleError{
+		err:     err,
+		roleARN: roleARN,
+	}
+}
+
+func NewMetricDataError(id string, statusCode string, message string) MetricDataError {
+	return MetricDataError{
+		id:         id,
+		statusCode: statusCode,
+		message:    message,
+	}
+}
+
 func (e ArgumentError) Error() string {
 	return fmt.Sprintf(`invalid argument "%s" for %s: %s`, e.value, e.key, e.err)
 }
@@ -48,3 +74,19 @@ func (e CloudWatchError) Error() string {
 func (e CloudWatchError) Unwrap() error {
 	return e.err
 }
+
+func (e AssumeRoleError) Error() string {
+	return fmt.Sprintf(`unable to assume role "%s": %s`, e.roleARN, e.err)
+}
+
+func (e AssumeRoleError) Unwrap() error {
+	return e.err
+}
+
+func (e MetricDataError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf(`query "%s" returned status %s`, e.id, e.statusCode)
+	}
+
+	return fmt.Sprintf(`query "%s" returned status %s: %s`, e.id, e.statusCode, e.message)
+}
@@ -68,3 +68,82 @@ func Test_CloudWatchError_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_AssumeRoleError_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		msg     string
+		roleARN string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "error",
+			args: args{
+				msg:     "a",
+				roleARN: "arn:aws:iam::123456789012:role/r",
+			},
+			expected: `unable to assume role "arn:aws:iam::123456789012:role/r": a`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewAssumeRoleError(errors.New(tc.args.msg), tc.args.roleARN)
+
+			assert.Equal(tc.expected, err.Error(), "Error")
+		})
+	}
+}
+
+func Test_MetricDataError_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	type args struct {
+		id         string
+		statusCode string
+		message    string
+	}
+
+	type testCase struct {
+		name     string
+		args     args
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name: "with message",
+			args: args{
+				id:         "e1",
+				statusCode: "Forbidden",
+				message:    "a",
+			},
+			expected: `query "e1" returned status Forbidden: a`,
+		},
+		{
+			name: "without message",
+			args: args{
+				id:         "e1",
+				statusCode: "InternalError",
+				message:    "",
+			},
+			expected: `query "e1" returned status InternalError`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewMetricDataError(tc.args.id, tc.args.statusCode, tc.args.message)
+
+			assert.Equal(tc.expected, err.Error(), "Error")
+		})
+	}
+}
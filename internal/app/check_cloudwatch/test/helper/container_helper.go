@@ -9,7 +9,7 @@ import (
 	"github.com/quickguard-oss/nagios-cloudwatch-plugin/internal/app/check_cloudwatch/log"
 )
 
-func SetCloudWatchClientFactory(t *testing.T, factory func() (types.Client, error)) {
+func SetCloudWatchClientFactory(t *testing.T, factory func(types.Options) (types.Client, error)) {
 	t.Helper()
 
 	t.Cleanup(func() {
@@ -25,6 +25,22 @@ func SetCloudWatchClientFactory(t *testing.T, factory func() (types.Client, erro
 	})
 }
 
+func SetLogsClientFactory(t *testing.T, factory func(types.Options) (types.LogsClient, error)) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		container.Reset()
+	})
+
+	orig := container.LogsClientFactory
+
+	container.LogsClientFactory = factory
+
+	t.Cleanup(func() {
+		container.LogsClientFactory = orig
+	})
+}
+
 func SetLoggerIO(t *testing.T, logger io.Writer) {
 	t.Helper()
 
@@ -46,3 +62,15 @@ func SetLogOutputDiscard(t *testing.T) {
 
 	SetLoggerIO(t, io.Discard)
 }
+
+func SetInputIO(t *testing.T, input io.Reader) {
+	t.Helper()
+
+	inputIO := container.InputIO
+
+	container.InputIO = input
+
+	t.Cleanup(func() {
+		container.InputIO = inputIO
+	})
+}
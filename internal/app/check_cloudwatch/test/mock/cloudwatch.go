@@ -20,3 +20,13 @@ func (m *CloudWatchClient) GetMetricData(
 
 	return args.Get(0).(*cloudwatch.GetMetricDataOutput), args.Error(1)
 }
+
+func (m *CloudWatchClient) DescribeAlarms(
+	ctx context.Context,
+	params *cloudwatch.DescribeAlarmsInput,
+	optFns ...func(*cloudwatch.Options),
+) (*cloudwatch.DescribeAlarmsOutput, error) {
+	args := m.Called(ctx, params)
+
+	return args.Get(0).(*cloudwatch.DescribeAlarmsOutput), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/stretchr/testify/mock"
+)
+
+type LogsClient struct {
+	mock.Mock
+}
+
+func (m *LogsClient) StartQuery(
+	ctx context.Context,
+	params *cloudwatchlogs.StartQueryInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.StartQueryOutput, error) {
+	args := m.Called(ctx, params)
+
+	return args.Get(0).(*cloudwatchlogs.StartQueryOutput), args.Error(1)
+}
+
+func (m *LogsClient) GetQueryResults(
+	ctx context.Context,
+	params *cloudwatchlogs.GetQueryResultsInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	args := m.Called(ctx, params)
+
+	return args.Get(0).(*cloudwatchlogs.GetQueryResultsOutput), args.Error(1)
+}
+
+func (m *LogsClient) StopQuery(
+	ctx context.Context,
+	params *cloudwatchlogs.StopQueryInput,
+	optFns ...func(*cloudwatchlogs.Options),
+) (*cloudwatchlogs.StopQueryOutput, error) {
+	args := m.Called(ctx, params)
+
+	return args.Get(0).(*cloudwatchlogs.StopQueryOutput), args.Error(1)
+}